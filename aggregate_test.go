@@ -0,0 +1,103 @@
+package rxgo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pmlpml/rxgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReduce(t *testing.T) {
+	res := []string{}
+	ob := rxgo.Just("a", "b", "c").Reduce(nil, func(ctx context.Context, acc string, x string) string {
+		return acc + x
+	})
+	ob.Subscribe(func(x string) {
+		res = append(res, x)
+	})
+	ob.Subscribe(func(x string) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []string{"abc", "abc"}, res, "Reduce Test Error!")
+
+	var sum interface{}
+	rxgo.Just(1, 2, 3, 4).Reduce(0, func(acc, x int) int {
+		if x == 2 {
+			panic(rxgo.FlowableError{Err: errors.New("any"), Elements: x})
+		}
+		return acc + x
+	}).Subscribe(rxgo.ObserverMonitor{
+		Next: func(x interface{}) {
+			sum = x
+		},
+	})
+	assert.Equal(t, 8, sum, "Reduce Error Test Error!")
+
+	assert.Panics(t, func() {
+		rxgo.Just(1).Reduce("0", func(acc, x int) int {
+			return acc + x
+		})
+	}, "Reduce Seed Test Error!")
+}
+
+func TestCount(t *testing.T) {
+	var res []interface{}
+	rxgo.Range(0, 5).Filter(func(x int) bool {
+		return x%2 == 0
+	}).Count().Subscribe(func(x int) {
+		res = append(res, x)
+	})
+	rxgo.Empty().Count().Subscribe(func(x int) {
+		res = append(res, x)
+	})
+
+	assert.Equal(t, []interface{}{3, 0}, res, "Count Test Error!")
+}
+
+func TestSumAndAverage(t *testing.T) {
+	var res []interface{}
+	observer := rxgo.ObserverMonitor{
+		Next: func(x interface{}) {
+			res = append(res, x)
+		},
+		Error: func(e error) {
+			if fe, ok := e.(rxgo.FlowableError); ok {
+				e = fe.Err
+			}
+			res = append(res, e)
+		},
+	}
+
+	rxgo.Just(1, 2, 3).Sum().Subscribe(observer)
+	rxgo.Just(uint8(1), uint8(2)).Sum().Subscribe(observer)
+	rxgo.Just(1, 2.5, "x").Sum().Subscribe(observer)
+	rxgo.Empty().Sum().Subscribe(observer)
+	rxgo.Just(1, 2, 3, 4).Average().Subscribe(observer)
+	rxgo.Empty().Average().Subscribe(observer)
+
+	assert.Equal(t, []interface{}{6, uint8(3), rxgo.ErrNotNumber, 3.5, 0, 2.5, rxgo.ErrEmptyFlow}, res, "Sum Test Error!")
+}
+
+func TestMinAndMax(t *testing.T) {
+	var res []interface{}
+	observer := rxgo.ObserverMonitor{
+		Next: func(x interface{}) {
+			res = append(res, x)
+		},
+		Error: func(e error) {
+			res = append(res, e)
+		},
+	}
+
+	rxgo.Just(3, 1, 4, 1, 5).Min(nil).Subscribe(observer)
+	rxgo.Just(3, 1, 4, 1, 5).Max(nil).Subscribe(observer)
+	rxgo.Just("b", "a", "c").Max(nil).Subscribe(observer)
+	rxgo.Just("bb", "a", "ccc").Min(func(ctx context.Context, a, b string) bool {
+		return len(a) < len(b)
+	}).Subscribe(observer)
+	rxgo.Empty().Max(nil).Subscribe(observer)
+
+	assert.Equal(t, []interface{}{1, 5, "c", "a", rxgo.ErrEmptyFlow}, res, "MinMax Test Error!")
+}
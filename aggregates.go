@@ -0,0 +1,321 @@
+// Copyright 2018 The SS.SYSU Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rxgo
+
+import (
+	"context"
+	"reflect"
+)
+
+// Reduce `func(acc anytype, x anytype) anytype` applies the function to the seed and the first item,
+// then feeds the result with the second item into the function and so on, it emits only the final result
+// when the Observable completes. Error items are not accumulated but flow to the downstream.
+func (parent *Observable) Reduce(seed interface{}, f interface{}) (o *Observable) {
+	o = parent.newTransformObservable("reduce")
+	o.flip = newAccumulator(seed, f)
+	o.operator = reduceOperater
+	return o
+}
+
+var reduceOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	acc := *o.flip.(*accumulator)
+	for x := range in {
+		if forwarded, end := o.forwardError(ctx, x, out); forwarded {
+			if end {
+				return
+			}
+			continue
+		}
+		_, stop, e := acc.accumulate(ctx, x)
+		if stop {
+			break
+		}
+		if e != nil {
+			if o.sendToFlow(ctx, e, out) {
+				return
+			}
+		}
+	}
+	o.sendToFlow(ctx, acc.value(), out)
+}}
+
+// Count counts items emitted by an Observable and emits the number when it completes.
+// Error items are not counted but flow to the downstream.
+func (parent *Observable) Count() (o *Observable) {
+	o = parent.newTransformObservable("count")
+	o.operator = countOperater
+	return o
+}
+
+var countOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	n := 0
+	for x := range in {
+		if forwarded, end := o.forwardError(ctx, x, out); forwarded {
+			if end {
+				return
+			}
+			continue
+		}
+		n++
+	}
+	o.sendToFlow(ctx, n, out)
+}}
+
+// Sum emits the sum of numbers emitted by an Observable when it completes. The sum has the type of
+// items if they are the same type, otherwise it is float64. The sum of no items is 0.
+// Items that are not numbers flow as FlowableError with ErrNotNumber.
+func (parent *Observable) Sum() (o *Observable) {
+	o = parent.newTransformObservable("sum")
+	o.operator = sumOperater
+	return o
+}
+
+var sumOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	var sum numberSum
+	if o.sumNumbers(ctx, &sum, in, out) {
+		return
+	}
+	o.sendToFlow(ctx, sum.value(), out)
+}}
+
+// Average emits the float64 average of numbers emitted by an Observable when it completes,
+// or ErrEmptyFlow if no number is emitted. Items that are not numbers flow as FlowableError with ErrNotNumber.
+func (parent *Observable) Average() (o *Observable) {
+	o = parent.newTransformObservable("average")
+	o.operator = averageOperater
+	return o
+}
+
+var averageOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	var sum numberSum
+	if o.sumNumbers(ctx, &sum, in, out) {
+		return
+	}
+	if sum.count == 0 {
+		o.sendToFlow(ctx, ErrEmptyFlow, out)
+		return
+	}
+	o.sendToFlow(ctx, sum.f/float64(sum.count), out)
+}}
+
+// add numbers of the inflow to sum
+func (o *Observable) sumNumbers(ctx context.Context, sum *numberSum, in chan interface{}, out chan interface{}) (end bool) {
+	for x := range in {
+		if forwarded, end := o.forwardError(ctx, x, out); forwarded {
+			if end {
+				return true
+			}
+			continue
+		}
+		if !sum.add(x) {
+			if o.sendToFlow(ctx, FlowableError{Err: ErrNotNumber, Elements: x}, out) {
+				return true
+			}
+		}
+	}
+	return
+}
+
+// sum of numbers with any numeric kind
+type numberSum struct {
+	typ   reflect.Type // type of the first number
+	mixed bool         // numbers are not the same type
+	count int
+	i     int64
+	u     uint64
+	f     float64
+}
+
+func (s *numberSum) add(x interface{}) bool {
+	v := reflect.ValueOf(x)
+	f, ok := toFloat(v)
+	if !ok {
+		return false
+	}
+	switch {
+	case isInt(v):
+		s.i += v.Int()
+	case isUint(v):
+		s.u += v.Uint()
+	}
+	s.f += f
+	if s.typ == nil {
+		s.typ = v.Type()
+	} else if s.typ != v.Type() {
+		s.mixed = true
+	}
+	s.count++
+	return true
+}
+
+func (s *numberSum) value() interface{} {
+	if s.typ == nil {
+		return 0
+	}
+	if s.mixed {
+		return s.f
+	}
+	v := reflect.ValueOf(s.f)
+	switch zero := reflect.Zero(s.typ); {
+	case isInt(zero):
+		v = reflect.ValueOf(s.i)
+	case isUint(zero):
+		v = reflect.ValueOf(s.u)
+	}
+	return v.Convert(s.typ).Interface()
+}
+
+// Min `func(a, b anytype) bool` emits the least item emitted by an Observable when it completes,
+// or ErrEmptyFlow if no item is emitted. The function reports whether a is less than b,
+// numbers and strings are compared by their natural order if it is nil.
+func (parent *Observable) Min(less interface{}) (o *Observable) {
+	o = parent.newCompareObservable("min", less)
+	o.operator = minOperater
+	return o
+}
+
+var minOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	o.compareItems(ctx, false, in, out)
+}}
+
+// Max `func(a, b anytype) bool` emits the greatest item emitted by an Observable when it completes,
+// or ErrEmptyFlow if no item is emitted. The function reports whether a is less than b,
+// numbers and strings are compared by their natural order if it is nil.
+func (parent *Observable) Max(less interface{}) (o *Observable) {
+	o = parent.newCompareObservable("max", less)
+	o.operator = maxOperater
+	return o
+}
+
+var maxOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	o.compareItems(ctx, true, in, out)
+}}
+
+func (parent *Observable) newCompareObservable(name string, less interface{}) (o *Observable) {
+	o = parent.newTransformObservable(name)
+	if less != nil {
+		// check validation of less
+		fv := reflect.ValueOf(less)
+		inType := []reflect.Type{typeAny, typeAny}
+		outType := []reflect.Type{typeBool}
+		b, ctx_sup := checkFuncUpcast(fv, inType, outType, true)
+		if !b {
+			panic(ErrFuncFlip)
+		}
+		o.flip_sup_ctx = ctx_sup
+		o.flip = fv.Interface()
+	}
+	return o
+}
+
+// emit the least or the greatest item of the inflow when it completes
+func (o *Observable) compareItems(ctx context.Context, max bool, in chan interface{}, out chan interface{}) {
+	var res interface{}
+	has_res := false
+	for x := range in {
+		if forwarded, end := o.forwardError(ctx, x, out); forwarded {
+			if end {
+				return
+			}
+			continue
+		}
+		if !has_res {
+			if _, ok := naturalLess(x, x); o.flip == nil && !ok {
+				if o.sendToFlow(ctx, FlowableError{Err: ErrNotOrdered, Elements: x}, out) {
+					return
+				}
+				continue
+			}
+			res, has_res = x, true
+			continue
+		}
+
+		var b, ok bool
+		var e error
+		if o.flip == nil {
+			b, ok = naturalLess(x, res)
+			if max {
+				b, ok = naturalLess(res, x)
+			}
+			if !ok {
+				e = FlowableError{Err: ErrNotOrdered, Elements: x}
+			}
+		} else {
+			a1, a2 := reflect.ValueOf(x), reflect.ValueOf(res)
+			if max {
+				a1, a2 = a2, a1
+			}
+			rs, skip, stop, fe := userFuncCallWithContext(ctx, reflect.ValueOf(o.flip), o.flip_sup_ctx, a1, a2)
+			if stop {
+				break
+			}
+			if skip {
+				continue
+			}
+			if e = fe; e == nil {
+				b = rs[0].Bool()
+			}
+		}
+		if e != nil {
+			if o.sendToFlow(ctx, e, out) {
+				return
+			}
+			continue
+		}
+		if b {
+			res = x
+		}
+	}
+
+	if !has_res {
+		o.sendToFlow(ctx, ErrEmptyFlow, out)
+		return
+	}
+	o.sendToFlow(ctx, res, out)
+}
+
+// compare numbers or strings by their natural order, ok is false if they can not be compared
+func naturalLess(a, b interface{}) (less, ok bool) {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch {
+	case isInt(va) && isInt(vb):
+		return va.Int() < vb.Int(), true
+	case isUint(va) && isUint(vb):
+		return va.Uint() < vb.Uint(), true
+	case va.Kind() == reflect.String && vb.Kind() == reflect.String:
+		return va.String() < vb.String(), true
+	}
+	fa, oka := toFloat(va)
+	fb, okb := toFloat(vb)
+	return fa < fb, oka && okb
+}
+
+func isInt(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+func isUint(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+func toFloat(v reflect.Value) (float64, bool) {
+	switch {
+	case isInt(v):
+		return float64(v.Int()), true
+	case isUint(v):
+		return float64(v.Uint()), true
+	case v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,214 @@
+package rxgo_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pmlpml/rxgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBufferWithCount(t *testing.T) {
+	res := [][]interface{}{}
+	rxgo.Range(0, 5).BufferWithCount(2, 0).Subscribe(func(x []interface{}) {
+		res = append(res, x)
+	})
+	assert.Equal(t, [][]interface{}{{0, 1}, {2, 3}, {4}}, res, "BufferWithCount Test Error!")
+
+	res = [][]interface{}{}
+	rxgo.Range(0, 5).BufferWithCount(3, 1).Subscribe(func(x []interface{}) {
+		res = append(res, x)
+	})
+	assert.Equal(t, [][]interface{}{{0, 1, 2}, {1, 2, 3}, {2, 3, 4}, {3, 4}, {4}}, res, "BufferWithCount Skip Test Error!")
+
+	res = [][]interface{}{}
+	rxgo.Range(0, 7).BufferWithCount(2, 3).Subscribe(func(x []interface{}) {
+		res = append(res, x)
+	})
+	assert.Equal(t, [][]interface{}{{0, 1}, {3, 4}, {6}}, res, "BufferWithCount Gap Test Error!")
+}
+
+func TestBufferWithTime(t *testing.T) {
+	res := []interface{}{}
+	ee := errors.New("Any")
+	rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+		send(1)
+		send(2)
+		send(ee)
+		time.Sleep(60 * time.Millisecond)
+		send(3)
+	}).BufferWithTime(30*time.Millisecond, 0).Subscribe(rxgo.ObserverMonitor{
+		Next: func(x interface{}) {
+			res = append(res, x)
+		},
+		Error: func(e error) {
+			res = append(res, e)
+		},
+	})
+
+	assert.Equal(t, []interface{}{ee, []interface{}{1, 2}, []interface{}{3}}, res, "BufferWithTime Test Error!")
+}
+
+func TestBufferWithTimeOrCount(t *testing.T) {
+	res := [][]interface{}{}
+	rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+		send(1)
+		send(2)
+		send(3)
+		send(4)
+		time.Sleep(60 * time.Millisecond)
+		send(5)
+	}).BufferWithTimeOrCount(30*time.Millisecond, 3).Subscribe(func(x []interface{}) {
+		res = append(res, x)
+	})
+
+	assert.Equal(t, [][]interface{}{{1, 2, 3}, {4}, {5}}, res, "BufferWithTimeOrCount Test Error!")
+}
+
+func TestBufferWithCancel(t *testing.T) {
+	var observer = rxgo.ObserverMonitor{}
+	observer.Context = func() context.Context {
+		ctx, cancel := context.WithCancel(context.Background())
+		observer.CancelObservables = cancel
+		return ctx
+	}
+	observer.AfterConnected = func() {
+		go func() {
+			<-time.After(10 * time.Millisecond)
+			observer.Unsubscribe()
+		}()
+	}
+
+	done := make(chan bool)
+	go func() {
+		rxgo.Never().BufferWithTime(time.Millisecond, 0).Subscribe(observer)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("Buffer is not stopped when cancelled!")
+	}
+}
+
+func TestWindowWithCount(t *testing.T) {
+	res := []int{}
+	windows := 0
+	rxgo.Range(0, 5).WindowWithCount(2).FlatMap(func(w *rxgo.Observable) *rxgo.Observable {
+		windows++
+		return w.Map(func(x int) int {
+			return x * 10
+		}).Reduce(0, func(acc, x int) int {
+			return acc + x
+		})
+	}).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+
+	assert.Equal(t, 3, windows, "WindowWithCount Count Test Error!")
+	assert.Equal(t, []int{10, 50, 40}, res, "WindowWithCount Test Error!")
+}
+
+func TestWindowWithObservable(t *testing.T) {
+	res := [][]interface{}{}
+	ready := make(chan bool)
+	opened := make(chan bool)
+	source := rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+		send(1)
+		send(2)
+		close(ready)
+		<-opened
+		send(3)
+	})
+	boundary := rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+		<-ready
+		send(0)
+		close(opened)
+	})
+
+	windows := []*rxgo.Observable{}
+	source.WindowWithObservable(boundary).Subscribe(func(w *rxgo.Observable) {
+		windows = append(windows, w)
+	})
+	// windows are connected lazily, so they can be subscribed after the source completed
+	for _, w := range windows {
+		w.BufferWithCount(10, 0).Subscribe(func(x []interface{}) {
+			res = append(res, x)
+		})
+	}
+
+	assert.Equal(t, [][]interface{}{{1, 2}, {3}}, res, "WindowWithObservable Test Error!")
+}
+
+func TestWindowWithTime(t *testing.T) {
+	res := []int{}
+	rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+		send(1)
+		send(2)
+		time.Sleep(60 * time.Millisecond)
+		send(3)
+	}).WindowWithTime(40 * time.Millisecond).FlatMap(func(w *rxgo.Observable) *rxgo.Observable {
+		return w.Count()
+	}).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+
+	assert.Equal(t, []int{2, 1}, res, "WindowWithTime Test Error!")
+}
+
+func TestGroupBy(t *testing.T) {
+	res := map[interface{}][]int{}
+	var mu sync.Mutex
+	rxgo.Range(0, 7).GroupBy(func(x int) string {
+		if x%2 == 0 {
+			return "even"
+		}
+		return "odd"
+	}).SubscribeOn(rxgo.ThreadingIO).FlatMap(func(g *rxgo.GroupedObservable) *rxgo.Observable {
+		return g.Reduce(nil, func(acc []int, x int) []int {
+			return append(acc, x)
+		}).Map(func(x []int) bool {
+			mu.Lock()
+			res[g.Key] = x
+			mu.Unlock()
+			return true
+		})
+	}).Subscribe(func(x bool) {
+	})
+
+	assert.Equal(t, map[interface{}][]int{"even": {0, 2, 4, 6}, "odd": {1, 3, 5}}, res, "GroupBy Test Error!")
+
+	errs := []bool{}
+	rxgo.Just(1, 2).GroupBy(func(x int) interface{} {
+		return struct{ v interface{} }{[]int{x}}
+	}).Subscribe(rxgo.ObserverMonitor{
+		Error: func(e error) {
+			errs = append(errs, errors.Is(e, rxgo.ErrKeyNotComparable))
+		},
+	})
+	assert.Equal(t, []bool{true, true}, errs, "GroupBy Unhashable Key Test Error!")
+}
+
+func TestGroupByWithIdle(t *testing.T) {
+	keys := []interface{}{}
+	counts := []int{}
+	rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+		send("a")
+		send("b")
+		send("a")
+		time.Sleep(60 * time.Millisecond)
+		send("a")
+	}).GroupByWithIdle(nil, 20*time.Millisecond).FlatMap(func(g *rxgo.GroupedObservable) *rxgo.Observable {
+		keys = append(keys, g.Key)
+		return g.Count()
+	}).Subscribe(func(x int) {
+		counts = append(counts, x)
+	})
+
+	assert.Equal(t, []interface{}{"a", "b", "a"}, keys, "GroupByWithIdle Key Test Error!")
+	assert.Equal(t, []int{2, 1, 1}, counts, "GroupByWithIdle Count Test Error!")
+}
@@ -0,0 +1,522 @@
+// Copyright 2018 The SS.SYSU Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rxgo
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// options of buffer operators
+type bufferFlip struct {
+	n, skip     int
+	span, shift time.Duration
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// BufferWithCount gathers items emitted by an Observable into []interface{} buffers of n items.
+// A new buffer is started every skip items, it is n if skip is not positive. Partial buffers are
+// emitted when the Observable completes. Error items are not buffered but flow to the downstream.
+func (parent *Observable) BufferWithCount(n, skip int) (o *Observable) {
+	if n <= 0 {
+		panic(ErrFuncFlip)
+	}
+	if skip <= 0 {
+		skip = n
+	}
+	o = parent.newTransformObservable("bufferWithCount")
+	o.flip = bufferFlip{n: n, skip: skip}
+	o.operator = bufferWithCountOperater
+	return o
+}
+
+var bufferWithCountOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	n, skip := o.flip.(bufferFlip).n, o.flip.(bufferFlip).skip
+	var buffers [][]interface{}
+	i := 0
+	for x := range in {
+		if forwarded, end := o.forwardError(ctx, x, out); forwarded {
+			if end {
+				return
+			}
+			continue
+		}
+		if i%skip == 0 {
+			buffers = append(buffers, make([]interface{}, 0, n))
+		}
+		i++
+		for j := range buffers {
+			buffers[j] = append(buffers[j], x)
+		}
+		// buffers are ordered by start, so only the first one may be full. Items in the gap
+		// between buffers are dropped if skip is greater than n
+		if len(buffers) > 0 && len(buffers[0]) == n {
+			if o.sendToFlow(ctx, buffers[0], out) {
+				return
+			}
+			buffers = buffers[1:]
+		}
+	}
+	for _, buf := range buffers {
+		if o.sendToFlow(ctx, buf, out) {
+			return
+		}
+	}
+}}
+
+// BufferWithTime gathers items emitted by an Observable into []interface{} buffers that last for span.
+// A new buffer is started every shift, it is span if shift is not positive. Empty buffers are not emitted,
+// partial buffers are emitted when the Observable completes. Error items are not buffered but flow to the downstream.
+func (parent *Observable) BufferWithTime(span, shift time.Duration) (o *Observable) {
+	if span <= 0 {
+		panic(ErrFuncFlip)
+	}
+	if shift <= 0 {
+		shift = span
+	}
+	o = parent.newTransformObservable("bufferWithTime")
+	o.flip = bufferFlip{span: span, shift: shift}
+	o.operator = bufferWithTimeOperater
+	return o
+}
+
+// a buffer closed at the deadline
+type timedBuffer struct {
+	items    []interface{}
+	deadline time.Time
+}
+
+var bufferWithTimeOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	span, shift := o.flip.(bufferFlip).span, o.flip.(bufferFlip).shift
+	now := time.Now()
+	buffers := []timedBuffer{{deadline: now.Add(span)}}
+	next_open := now.Add(shift)
+
+	timer := time.NewTimer(minDuration(span, shift))
+	defer timer.Stop()
+	for {
+		select {
+		case x, ok := <-in:
+			if !ok {
+				for _, buf := range buffers {
+					if len(buf.items) > 0 && o.sendToFlow(ctx, buf.items, out) {
+						return
+					}
+				}
+				return
+			}
+			if forwarded, end := o.forwardError(ctx, x, out); forwarded {
+				if end {
+					return
+				}
+				continue
+			}
+			for i := range buffers {
+				buffers[i].items = append(buffers[i].items, x)
+			}
+		case now := <-timer.C:
+			// buffers are ordered by deadline
+			for len(buffers) > 0 && !buffers[0].deadline.After(now) {
+				if len(buffers[0].items) > 0 && o.sendToFlow(ctx, buffers[0].items, out) {
+					return
+				}
+				buffers = buffers[1:]
+			}
+			for ; !next_open.After(now); next_open = next_open.Add(shift) {
+				buffers = append(buffers, timedBuffer{deadline: next_open.Add(span)})
+			}
+			next := next_open
+			if len(buffers) > 0 && buffers[0].deadline.Before(next) {
+				next = buffers[0].deadline
+			}
+			timer.Reset(next.Sub(now))
+		case <-ctx.Done():
+			return
+		}
+	}
+}}
+
+// BufferWithTimeOrCount gathers items emitted by an Observable into []interface{} buffers, a buffer
+// is emitted when it has n items or it lasts for span. Empty buffers are not emitted, the partial buffer
+// is emitted when the Observable completes. Error items are not buffered but flow to the downstream.
+func (parent *Observable) BufferWithTimeOrCount(span time.Duration, n int) (o *Observable) {
+	if span <= 0 || n <= 0 {
+		panic(ErrFuncFlip)
+	}
+	o = parent.newTransformObservable("bufferWithTimeOrCount")
+	o.flip = bufferFlip{n: n, span: span}
+	o.operator = bufferWithTimeOrCountOperater
+	return o
+}
+
+var bufferWithTimeOrCountOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	n, span := o.flip.(bufferFlip).n, o.flip.(bufferFlip).span
+	buf := make([]interface{}, 0, n)
+
+	timer := time.NewTimer(span)
+	defer func() {
+		timer.Stop()
+	}()
+	for {
+		select {
+		case x, ok := <-in:
+			if !ok {
+				if len(buf) > 0 {
+					o.sendToFlow(ctx, buf, out)
+				}
+				return
+			}
+			if forwarded, end := o.forwardError(ctx, x, out); forwarded {
+				if end {
+					return
+				}
+				continue
+			}
+			if buf = append(buf, x); len(buf) < n {
+				continue
+			}
+			// restart the span for the next buffer
+			timer.Stop()
+			timer = time.NewTimer(span)
+		case <-timer.C:
+			timer.Reset(span)
+			if len(buf) == 0 {
+				continue
+			}
+		case <-ctx.Done():
+			return
+		}
+		if o.sendToFlow(ctx, buf, out) {
+			return
+		}
+		buf = make([]interface{}, 0, n)
+	}
+}}
+
+// an unbounded queue of items that is written by an operator and read by an inner Observable,
+// so the operator never blocks on inner Observables that are connected lazily or never.
+type innerFlow struct {
+	mu     sync.Mutex
+	items  []interface{}
+	closed bool
+	signal chan struct{} // notify the reader that items or closed changed
+}
+
+func newInnerFlow() *innerFlow {
+	return &innerFlow{signal: make(chan struct{}, 1)}
+}
+
+func (f *innerFlow) push(x interface{}) {
+	f.mu.Lock()
+	f.items = append(f.items, x)
+	f.mu.Unlock()
+	f.notify()
+}
+
+func (f *innerFlow) close() {
+	f.mu.Lock()
+	f.closed = true
+	f.mu.Unlock()
+	f.notify()
+}
+
+func (f *innerFlow) notify() {
+	select {
+	case f.signal <- struct{}{}:
+	default:
+	}
+}
+
+var innerSource = rangeSource
+
+// create an Observable that emits items of the queue until it is closed. Items are taken
+// away when they are emitted, so the Observable should be subscribed only once.
+func (f *innerFlow) observable(name string) *Observable {
+	o := newGeneratorObservable(name)
+
+	o.flip = func(ctx context.Context, out chan interface{}) {
+		f.emit(ctx, o, out)
+	}
+	o.operator = innerSource
+	return o
+}
+
+// emit items of the queue to out of o until it is closed
+func (f *innerFlow) emit(ctx context.Context, o *Observable, out chan interface{}) {
+	for {
+		f.mu.Lock()
+		items, closed := f.items, f.closed
+		f.items = nil
+		f.mu.Unlock()
+
+		for _, item := range items {
+			if b := o.sendToFlow(ctx, item, out); b {
+				return
+			}
+		}
+		if closed {
+			return
+		}
+		select {
+		case <-f.signal:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// windows of an operator, only the latest one is open
+type windowFlow struct {
+	ctx  context.Context
+	o    *Observable
+	out  chan interface{}
+	flow *innerFlow
+}
+
+// close the current window and emit a new one
+func (w *windowFlow) open() (end bool) {
+	w.close()
+	w.flow = newInnerFlow()
+	return w.o.sendToFlow(w.ctx, w.flow.observable("window"), w.out)
+}
+
+func (w *windowFlow) close() {
+	if w.flow != nil {
+		w.flow.close()
+		w.flow = nil
+	}
+}
+
+// WindowWithCount divides items emitted by an Observable into windows of n items, and emits each window
+// as an Observable that should be subscribed only once. A window is opened when an item arrives and no window is open.
+// Error items flow to the downstream instead of the windows.
+func (parent *Observable) WindowWithCount(n int) (o *Observable) {
+	if n <= 0 {
+		panic(ErrFuncFlip)
+	}
+	o = parent.newTransformObservable("windowWithCount")
+	o.flip = bufferFlip{n: n}
+	o.operator = windowWithCountOperater
+	return o
+}
+
+var windowWithCountOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	n := o.flip.(bufferFlip).n
+	w := &windowFlow{ctx: ctx, o: o, out: out}
+	defer w.close()
+	i := 0
+	for x := range in {
+		if forwarded, end := o.forwardError(ctx, x, out); forwarded {
+			if end {
+				return
+			}
+			continue
+		}
+		if i == 0 && w.open() {
+			return
+		}
+		w.flow.push(x)
+		if i++; i == n {
+			w.close()
+			i = 0
+		}
+	}
+}}
+
+// WindowWithTime divides items emitted by an Observable into windows that last for span, and emits each window
+// as an Observable that should be subscribed only once. Windows are emitted even if they are empty.
+// Error items flow to the downstream instead of the windows.
+func (parent *Observable) WindowWithTime(span time.Duration) (o *Observable) {
+	if span <= 0 {
+		panic(ErrFuncFlip)
+	}
+	o = parent.newTransformObservable("windowWithTime")
+	o.flip = bufferFlip{span: span}
+	o.operator = windowWithTimeOperater
+	return o
+}
+
+var windowWithTimeOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	ticker := time.NewTicker(o.flip.(bufferFlip).span)
+	defer ticker.Stop()
+	o.windowWithSignal(ctx, in, out, ticker.C)
+}}
+
+// WindowWithObservable divides items emitted by an Observable into windows, and emits each window
+// as an Observable that should be subscribed only once. A new window is opened whenever the boundary
+// Observable emits an item. Error items flow to the downstream instead of the windows.
+func (parent *Observable) WindowWithObservable(boundary *Observable) (o *Observable) {
+	o = parent.newTransformObservable("windowWithObservable")
+	o.flip = boundary
+	o.operator = windowWithObservableOperater
+	return o
+}
+
+var windowWithObservableOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	bctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	o.windowWithSignal(ctx, in, out, o.flip.(*Observable).connectFlow(bctx))
+}}
+
+// open a new window whenever the signal channel receives
+func (o *Observable) windowWithSignal(ctx context.Context, in chan interface{}, out chan interface{}, signal interface{}) {
+	w := &windowFlow{ctx: ctx, o: o, out: out}
+	defer w.close()
+	if w.open() {
+		return
+	}
+
+	cases := []reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(in)},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(signal)},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+	}
+	for {
+		chosen, recv, recvOK := reflect.Select(cases)
+		switch chosen {
+		case 0:
+			if !recvOK {
+				return
+			}
+			x := recv.Interface()
+			if forwarded, end := o.forwardError(ctx, x, out); forwarded {
+				if end {
+					return
+				}
+				continue
+			}
+			w.flow.push(x)
+		case 1:
+			if !recvOK {
+				// the boundary completed, the current window lasts until the Observable completes
+				cases[1].Chan = reflect.Value{}
+				continue
+			}
+			if w.open() {
+				return
+			}
+		case 2:
+			return
+		}
+	}
+}
+
+// A GroupedObservable emits items of an Observable that have the same key.
+type GroupedObservable struct {
+	*Observable
+	Key interface{}
+}
+
+// GroupBy `func(x anytype) anytype` divides items emitted by an Observable into groups by their keys,
+// and emits each group as a *GroupedObservable that should be subscribed only once. Each group runs
+// in its own goroutine when it is connected, and it is closed when the Observable completes.
+// The key is the item itself if f is nil. Error items flow to the downstream instead of the groups.
+func (parent *Observable) GroupBy(f interface{}) (o *Observable) {
+	return parent.GroupByWithIdle(f, 0)
+}
+
+// GroupByWithIdle is GroupBy that closes groups receiving no items for idle, so memory does not grow
+// unbounded for high-cardinality keys. A new group is emitted if an item with the key of a closed group arrives.
+func (parent *Observable) GroupByWithIdle(f interface{}, idle time.Duration) (o *Observable) {
+	df := &distinctFlip{ttl: idle}
+	df.setKeyFunc(f)
+
+	o = parent.newTransformObservable("groupBy")
+	o.flip_accept_error = checkFuncAcceptError(df.key)
+	o.flip = df
+	o.operator = groupByOperater
+	return o
+}
+
+// a group of GroupBy
+type group struct {
+	flow *innerFlow
+	last time.Time // when the latest item arrived
+}
+
+var groupByOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	df := o.flip.(*distinctFlip)
+	idle := df.ttl
+	groups := make(map[interface{}]*group)
+	var timer *time.Timer
+	var expiry <-chan time.Time
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+		for _, g := range groups {
+			g.flow.close()
+		}
+	}()
+
+	for {
+		select {
+		case x, ok := <-in:
+			if !ok {
+				return
+			}
+			if forwarded, end := o.forwardError(ctx, x, out); forwarded {
+				if end {
+					return
+				}
+				continue
+			}
+			key, skip, stop, e := df.mapKeyOf(ctx, x)
+			if stop {
+				return
+			}
+			if skip {
+				continue
+			}
+			if e != nil {
+				if o.sendToFlow(ctx, e, out) {
+					return
+				}
+				continue
+			}
+
+			g, ok := groups[key]
+			if !ok {
+				g = &group{flow: newInnerFlow()}
+				groups[key] = g
+				if o.sendToFlow(ctx, &GroupedObservable{g.flow.observable("group"), key}, out) {
+					return
+				}
+			}
+			g.flow.push(x)
+			g.last = time.Now()
+			if idle > 0 && expiry == nil {
+				timer = time.NewTimer(idle)
+				expiry = timer.C
+			}
+		case now := <-expiry:
+			// close idle groups, and wait for the next one to be idle
+			var next time.Duration
+			for key, g := range groups {
+				if d := idle - now.Sub(g.last); d <= 0 {
+					g.flow.close()
+					delete(groups, key)
+				} else if next == 0 || d < next {
+					next = d
+				}
+			}
+			if len(groups) == 0 {
+				expiry = nil
+			} else {
+				timer.Reset(next)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}}
@@ -0,0 +1,227 @@
+package rxgo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pmlpml/rxgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	res := []int{}
+	rxgo.Merge(rxgo.Just(1, 2, 3), rxgo.Range(10, 13), rxgo.Empty()).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+	sort.Ints(res)
+	assert.Equal(t, []int{1, 2, 3, 10, 11, 12}, res, "Merge Test Error!")
+
+	res = []int{}
+	rxgo.Just(1, 2).Map(func(x int) int {
+		return x * 100
+	}).MergeWith(rxgo.Just(3)).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+	sort.Ints(res)
+	assert.Equal(t, []int{3, 100, 200}, res, "MergeWith Test Error!")
+}
+
+func TestMergeWithCancel(t *testing.T) {
+	res := []int{}
+	var observer = rxgo.ObserverMonitor{}
+	observer.Next = func(x interface{}) {
+		res = append(res, x.(int))
+		if len(res) >= 3 {
+			observer.Unsubscribe()
+		}
+	}
+	observer.Context = func() context.Context {
+		ctx, cancel := context.WithCancel(context.Background())
+		observer.CancelObservables = cancel
+		return ctx
+	}
+
+	done := make(chan bool)
+	go func() {
+		rxgo.Merge(rxgo.Range(0, 1000), rxgo.Never()).Subscribe(observer)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("Merge is not stopped when cancelled!")
+	}
+	assert.True(t, len(res) < 100, "Merge cancel failure!")
+}
+
+func TestConcat(t *testing.T) {
+	res := []int{}
+	rxgo.Concat(rxgo.Just(1, 2), rxgo.Empty(), rxgo.Range(10, 12)).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []int{1, 2, 10, 11}, res, "Concat Test Error!")
+
+	var mu sync.Mutex
+	connected := false
+	first := rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+		send(3)
+		mu.Lock()
+		assert.False(t, connected, "Concat connected the next Observable too early!")
+		mu.Unlock()
+	})
+	second := rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+		mu.Lock()
+		connected = true
+		mu.Unlock()
+		send(5)
+	})
+
+	res = []int{}
+	rxgo.Concat(first, second).StartWith(1, 2).Map(func(x int) int {
+		return x
+	}).EndWith(9).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []int{1, 2, 3, 5, 9}, res, "StartWith Test Error!")
+}
+
+func TestZip(t *testing.T) {
+	res := []string{}
+	rxgo.Zip(func(a int, b string, c float64) string {
+		return fmt.Sprint(a, b, c)
+	}, rxgo.Range(1, 100), rxgo.Just("a", "b", "c"), rxgo.Just(0.5, 1.5, 2.5, 3.5)).Subscribe(func(x string) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []string{"1a0.5", "2b1.5", "3c2.5"}, res, "Zip Test Error!")
+
+	var count int32
+	rxgo.Zip(func(a, b int) int {
+		return a + b
+	}, rxgo.Start(func() (int, bool) {
+		return int(atomic.AddInt32(&count, 1)), false
+	}), rxgo.Just(10)).Subscribe(func(x int) {
+		assert.Equal(t, 11, x, "Zip Item Test Error!")
+	})
+	assert.True(t, atomic.LoadInt32(&count) < 2*int32(rxgo.BufferLen), "Zip Backpressure Test Error!")
+
+	res = []string{}
+	rxgo.Zip(func(a, b int) string {
+		return fmt.Sprint(a, b)
+	}, rxgo.Never(), rxgo.Empty()).Subscribe(func(x string) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []string{}, res, "Zip Completed Test Error!")
+
+	assert.Panics(t, func() {
+		rxgo.Zip(func(a int) int { return a }, rxgo.Just(1), rxgo.Just(2))
+	}, "Zip Zipper Test Error!")
+}
+
+func TestCombineLatest(t *testing.T) {
+	res := []string{}
+	first := make(chan bool)
+	second := make(chan bool)
+	a := rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+		send(1)
+		<-first
+		send(2)
+	})
+	b := rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+		send("x")
+		<-second
+		send("y")
+	})
+
+	rxgo.CombineLatest(func(x int, s string) string {
+		return fmt.Sprint(x, s)
+	}, a, b).Subscribe(func(x string) {
+		res = append(res, x)
+		switch len(res) {
+		case 1:
+			close(first)
+		case 2:
+			close(second)
+		}
+	})
+	assert.Equal(t, []string{"1x", "2x", "2y"}, res, "CombineLatest Test Error!")
+
+	res = []string{}
+	rxgo.CombineLatest(func(x int, s string) string {
+		return fmt.Sprint(x, s)
+	}, rxgo.Just(1, 2), rxgo.Empty()).Subscribe(func(x string) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []string{}, res, "CombineLatest Empty Test Error!")
+}
+
+func TestWithLatestFrom(t *testing.T) {
+	res := []interface{}{}
+	ee := errors.New("Any")
+	ready := make(chan bool)
+	other := rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+		send(ee)
+		send(10)
+		close(ready)
+		<-ctx.Done()
+	})
+	source := rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+		<-ready
+		send(1)
+		send(2)
+	})
+
+	source.WithLatestFrom(other, func(ctx context.Context, x, latest int) int {
+		return x + latest
+	}).Subscribe(rxgo.ObserverMonitor{
+		Next: func(x interface{}) {
+			res = append(res, x)
+		},
+		Error: func(e error) {
+			res = append(res, e)
+		},
+	})
+	assert.Equal(t, []interface{}{ee, 11, 12}, res, "WithLatestFrom Test Error!")
+}
+
+func TestAmb(t *testing.T) {
+	res := []int{}
+	var stopped int32
+	slow := rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+		select {
+		case <-time.After(time.Second):
+			send(100)
+		case <-ctx.Done():
+			atomic.AddInt32(&stopped, 1)
+		}
+	})
+	fast := rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+		send(1)
+		send(2)
+	})
+
+	start := time.Now()
+	rxgo.Amb(slow, fast, rxgo.Never()).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+
+	assert.Equal(t, []int{1, 2}, res, "Amb Test Error!")
+	assert.True(t, time.Since(start) < 500*time.Millisecond, "Amb Losers Test Error!")
+
+	for i := 0; i < 100 && atomic.LoadInt32(&stopped) == 0; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&stopped), "Amb Cancel Test Error!")
+
+	res = []int{}
+	rxgo.Amb().Subscribe(func(x int) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []int{}, res, "Amb Empty Test Error!")
+}
@@ -0,0 +1,377 @@
+// Copyright 2018 The SS.SYSU Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rxgo
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+var mergeSource = rangeSource
+
+// Merge combines multiple Observables into one by merging their items concurrently,
+// it completes when all of them complete.
+func Merge(obs ...*Observable) *Observable {
+	o := newGeneratorObservable("Merge")
+
+	o.flip = func(ctx context.Context, out chan interface{}) {
+		o.mergeFlows(ctx, connectFlows(ctx, obs), out)
+	}
+	o.operator = mergeSource
+	return o
+}
+
+// MergeWith merges items emitted by the Observable and other Observables concurrently,
+// it completes when all of them complete.
+func (parent *Observable) MergeWith(obs ...*Observable) (o *Observable) {
+	o = parent.newTransformObservable("mergeWith")
+	o.flip = obs
+	o.operator = mergeWithOperater
+	return o
+}
+
+var mergeWithOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	flows := append([]chan interface{}{in}, connectFlows(ctx, o.flip.([]*Observable))...)
+	o.mergeFlows(ctx, flows, out)
+}}
+
+// connect Observables used inside an operator and returns their outflows
+func connectFlows(ctx context.Context, obs []*Observable) []chan interface{} {
+	flows := make([]chan interface{}, len(obs))
+	for i, ob := range obs {
+		flows[i] = ob.connectFlow(ctx)
+	}
+	return flows
+}
+
+// send items of all flows to the outflow, and returns when all of them are closed
+func (o *Observable) mergeFlows(ctx context.Context, flows []chan interface{}, out chan interface{}) {
+	var wg sync.WaitGroup
+	for _, flow := range flows {
+		wg.Add(1)
+		go func(flow chan interface{}) {
+			defer wg.Done()
+			for x := range flow {
+				if o.sendToFlow(ctx, x, out) {
+					return
+				}
+			}
+		}(flow)
+	}
+	wg.Wait()
+}
+
+var concatSource = rangeSource
+
+// Concat emits items of multiple Observables one after another, the next Observable is connected
+// only after the previous one completes.
+func Concat(obs ...*Observable) *Observable {
+	o := newGeneratorObservable("Concat")
+
+	o.flip = func(ctx context.Context, out chan interface{}) {
+		for _, ob := range obs {
+			for item := range ob.connectFlow(ctx) {
+				if b := o.sendToFlow(ctx, item, out); b {
+					return
+				}
+			}
+		}
+	}
+	o.operator = concatSource
+	return o
+}
+
+// StartWith emits the items before items emitted by the Observable, which is connected after the items are emitted.
+func (parent *Observable) StartWith(items ...interface{}) *Observable {
+	o := Concat(Just(items...), parent)
+	o.Name = "StartWith"
+	return o
+}
+
+// EndWith emits the items after the Observable completes.
+func (parent *Observable) EndWith(items ...interface{}) *Observable {
+	o := Concat(parent, Just(items...))
+	o.Name = "EndWith"
+	return o
+}
+
+var zipSource = rangeSource
+
+// Zip combines items of multiple Observables by the function `func(a, b, ... anytype) anytype` element-wise,
+// it emits the result of the first items of all Observables, then the second items and so on. Zip completes
+// when any Observable completes. Each Observable has a queue of BufferLen items, it is blocked when the queue
+// is full. Error items flow to the downstream directly.
+func Zip(zipper interface{}, obs ...*Observable) *Observable {
+	fv, ctx_sup := checkCombiner(zipper, len(obs))
+	if len(obs) == 0 {
+		panic(ErrFuncFlip)
+	}
+
+	o := newGeneratorObservable("Zip")
+	o.flip_sup_ctx = ctx_sup
+
+	o.flip = func(ctx context.Context, out chan interface{}) {
+		// wait for the queues to stop after cancelled, they may send error items
+		var wg sync.WaitGroup
+		defer wg.Wait()
+		zctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		queues := o.queueFlows(zctx, connectFlows(zctx, obs), out, &wg)
+
+		params := make([]reflect.Value, len(queues))
+		cases := make([]reflect.SelectCase, len(queues))
+		for {
+			// receive from all queues at once, so that a drained queue of a completed Observable
+			// is seen even if the others are stalled
+			for i, queue := range queues {
+				cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(queue)}
+			}
+			for n := 0; n < len(queues); n++ {
+				i, x, ok := reflect.Select(cases)
+				if !ok {
+					return
+				}
+				params[i] = reflect.ValueOf(x.Interface())
+				// the case with zero Chan is ignored
+				cases[i].Chan = reflect.Value{}
+			}
+			if o.combine(ctx, fv, params, out) {
+				return
+			}
+		}
+	}
+	o.operator = zipSource
+	return o
+}
+
+// move items of flows into bounded queues, error items are sent to the outflow directly
+func (o *Observable) queueFlows(ctx context.Context, flows []chan interface{}, out chan interface{}, wg *sync.WaitGroup) []chan interface{} {
+	queues := make([]chan interface{}, len(flows))
+	for i, flow := range flows {
+		queues[i] = make(chan interface{}, BufferLen)
+		wg.Add(1)
+		go func(flow, queue chan interface{}) {
+			defer wg.Done()
+			defer close(queue)
+			for x := range flow {
+				if e, ok := x.(error); ok {
+					if o.sendToFlow(ctx, e, out) {
+						return
+					}
+					continue
+				}
+				select {
+				case queue <- x:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(flow, queues[i])
+	}
+	return queues
+}
+
+var combineLatestSource = rangeSource
+
+// CombineLatest combines the latest items of multiple Observables by the function `func(a, b, ... anytype) anytype`
+// whenever any of them emits an item, after all of them have emitted at least one item. It completes when all
+// Observables complete, or any of them completes without emitting. Error items flow to the downstream directly.
+func CombineLatest(combiner interface{}, obs ...*Observable) *Observable {
+	fv, ctx_sup := checkCombiner(combiner, len(obs))
+	if len(obs) == 0 {
+		panic(ErrFuncFlip)
+	}
+
+	o := newGeneratorObservable("CombineLatest")
+	o.flip_sup_ctx = ctx_sup
+
+	o.flip = func(ctx context.Context, out chan interface{}) {
+		cctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var mu sync.Mutex
+		latest := make([]reflect.Value, len(obs))
+		missing := len(obs)
+		var wg sync.WaitGroup
+		for i, flow := range connectFlows(cctx, obs) {
+			wg.Add(1)
+			go func(i int, flow chan interface{}) {
+				defer wg.Done()
+				for x := range flow {
+					if e, ok := x.(error); ok {
+						if o.sendToFlow(cctx, e, out) {
+							return
+						}
+						continue
+					}
+
+					mu.Lock()
+					if !latest[i].IsValid() {
+						missing--
+					}
+					latest[i] = reflect.ValueOf(x)
+					end := false
+					if missing == 0 {
+						// combine and send in the lock, so that results follow the order of items
+						end = o.combine(cctx, fv, latest, out)
+					}
+					mu.Unlock()
+					if end {
+						cancel()
+						return
+					}
+				}
+				mu.Lock()
+				if !latest[i].IsValid() {
+					// nothing can be combined any more
+					cancel()
+				}
+				mu.Unlock()
+			}(i, flow)
+		}
+		wg.Wait()
+	}
+	o.operator = combineLatestSource
+	return o
+}
+
+// WithLatestFrom combines each item emitted by the Observable with the latest item of the other Observable
+// by the function `func(x, latest anytype) anytype`. Items are dropped until the other Observable emits an item.
+// Error items of both Observables flow to the downstream directly.
+func (parent *Observable) WithLatestFrom(other *Observable, combiner interface{}) (o *Observable) {
+	fv, ctx_sup := checkCombiner(combiner, 2)
+
+	o = parent.newTransformObservable("withLatestFrom")
+	o.flip_sup_ctx = ctx_sup
+	o.flip = [2]interface{}{other, fv}
+	o.operator = withLatestFromOperater
+	return o
+}
+
+var withLatestFromOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	other, fv := o.flip.([2]interface{})[0].(*Observable), o.flip.([2]interface{})[1].(reflect.Value)
+	octx, cancel := context.WithCancel(ctx)
+	done := make(chan bool)
+	defer func() {
+		// the other Observable may send error items, so wait for it
+		cancel()
+		<-done
+	}()
+
+	var mu sync.Mutex
+	var latest reflect.Value
+	go func() {
+		defer close(done)
+		for x := range other.connectFlow(octx) {
+			if e, ok := x.(error); ok {
+				if o.sendToFlow(octx, e, out) {
+					return
+				}
+				continue
+			}
+			mu.Lock()
+			latest = reflect.ValueOf(x)
+			mu.Unlock()
+		}
+	}()
+
+	for x := range in {
+		if forwarded, end := o.forwardError(ctx, x, out); forwarded {
+			if end {
+				return
+			}
+			continue
+		}
+		mu.Lock()
+		y := latest
+		mu.Unlock()
+		if !y.IsValid() {
+			continue
+		}
+		if o.combine(ctx, fv, []reflect.Value{reflect.ValueOf(x), y}, out) {
+			return
+		}
+	}
+}}
+
+// check validation of combiner `func(a, b, ... anytype) anytype` with n parameters
+func checkCombiner(combiner interface{}, n int) (fv reflect.Value, ctx_sup bool) {
+	fv = reflect.ValueOf(combiner)
+	inType := make([]reflect.Type, n)
+	for i := range inType {
+		inType[i] = typeAny
+	}
+	outType := []reflect.Type{typeAny}
+	b, ctx_sup := checkFuncUpcast(fv, inType, outType, true)
+	if !b {
+		panic(ErrFuncFlip)
+	}
+	return
+}
+
+// call combiner with params and send the result
+func (o *Observable) combine(ctx context.Context, fv reflect.Value, params []reflect.Value, out chan interface{}) (end bool) {
+	rs, skip, stop, e := userFuncCallWithContext(ctx, fv, o.flip_sup_ctx, params...)
+	if stop {
+		return true
+	}
+	if skip {
+		return false
+	}
+	var item interface{} = e
+	if e == nil {
+		item = rs[0].Interface()
+	}
+	return o.sendToFlow(ctx, item, out)
+}
+
+var ambSource = rangeSource
+
+// Amb mirrors the first Observable that emits an item or completes, and stops all others.
+// It completes at once if there is no Observable.
+func Amb(obs ...*Observable) *Observable {
+	o := newGeneratorObservable("Amb")
+
+	o.flip = func(ctx context.Context, out chan interface{}) {
+		if len(obs) == 0 {
+			return
+		}
+		cancels := make([]context.CancelFunc, len(obs))
+		cases := make([]reflect.SelectCase, len(obs)+1)
+		for i, ob := range obs {
+			var actx context.Context
+			actx, cancels[i] = context.WithCancel(ctx)
+			cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ob.connectFlow(actx))}
+		}
+		cases[len(obs)] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())}
+
+		chosen, recv, recvOK := reflect.Select(cases)
+		// the losers exit promptly for their contexts are cancelled
+		for i, cancel := range cancels {
+			if i != chosen {
+				cancel()
+			}
+		}
+		if chosen == len(obs) {
+			return
+		}
+		defer cancels[chosen]()
+		if !recvOK {
+			return
+		}
+
+		if b := o.sendToFlow(ctx, recv.Interface(), out); b {
+			return
+		}
+		for item := range cases[chosen].Chan.Interface().(chan interface{}) {
+			if b := o.sendToFlow(ctx, item, out); b {
+				return
+			}
+		}
+	}
+	o.operator = ambSource
+	return o
+}
@@ -0,0 +1,130 @@
+// Copyright 2018 The SS.SYSU Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rxgo
+
+import (
+	"context"
+	"sync"
+)
+
+// ConnectableObservable shares one subscription of the upstream Observables among its subscribers.
+// The upstream does not run when it is subscribed to, but only when Connect is called.
+// A new subscription of the upstream is made by Connect after the previous one terminated.
+type ConnectableObservable struct {
+	*Observable // emits the items of the shared subscription
+	source      *Observable
+	mu          sync.Mutex
+	conn        *connection // the current subscription
+}
+
+// a subscription of the upstream shared by subscribers
+type connection struct {
+	subject *PublishSubject    // multicasts the items of the subscription
+	cancel  context.CancelFunc // stops the subscription, it is nil if not connected
+	refs    int                // subscribers of RefCount
+}
+
+func newConnection() *connection {
+	return &connection{subject: NewPublishSubject()}
+}
+
+// Publish creates a ConnectableObservable sharing the items of the Observable.
+func (parent *Observable) Publish() *ConnectableObservable {
+	c := &ConnectableObservable{source: parent, conn: newConnection()}
+	c.Observable = c.observable("publish", false)
+	return c
+}
+
+// create an Observable subscribing to the current connection, it is counted by RefCount if ref is true
+func (c *ConnectableObservable) observable(name string, ref bool) *Observable {
+	o := newGeneratorObservable(name)
+	o.flip = func() (*innerFlow, func()) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		conn := c.conn
+		f, unsubscribe := conn.subject.subscribe(nil)
+		if !ref {
+			return f, unsubscribe
+		}
+
+		// the subscriber is registered before connecting, so that it receives all items
+		if conn.refs++; conn.refs == 1 {
+			c.connect()
+		}
+		return f, func() {
+			unsubscribe()
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			if conn.refs--; conn.refs == 0 && conn.cancel != nil {
+				c.disconnect(conn)
+			}
+		}
+	}
+	o.operator = subjectSource
+	return o
+}
+
+// Connect subscribes to the upstream Observables if it is not connected, and returns the function
+// that stops the subscription. The subscribers complete when the subscription terminates.
+func (c *ConnectableObservable) Connect() (cancel func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.connect()
+}
+
+// connect the upstream, the caller holds the lock
+func (c *ConnectableObservable) connect() (cancel func()) {
+	conn := c.conn
+	cancel = func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.disconnect(conn)
+	}
+	if conn.cancel != nil {
+		return cancel
+	}
+	ctx, stop := context.WithCancel(context.Background())
+	conn.cancel = stop
+
+	go func() {
+		c.source.Subscribe(ObserverMonitor{
+			Next: conn.subject.OnNext,
+			Error: func(e error) {
+				conn.subject.OnError(e)
+			},
+			Context: func() context.Context {
+				return ctx
+			},
+		})
+		cancel()
+		conn.subject.OnCompleted()
+	}()
+	return cancel
+}
+
+// stop the subscription and detach it, so that the subscribers arriving from now on wait for the next
+// connection even if the stopped one has not terminated, the caller holds the lock
+func (c *ConnectableObservable) disconnect(conn *connection) {
+	if conn.cancel != nil {
+		conn.cancel()
+	}
+	if c.conn == conn {
+		c.conn = newConnection()
+	}
+}
+
+// RefCount creates an Observable that connects the ConnectableObservable when the first subscriber subscribes,
+// and stops the subscription when the last subscriber unsubscribes.
+func (c *ConnectableObservable) RefCount() *Observable {
+	return c.observable("refCount", true)
+}
+
+// Share creates an Observable sharing one subscription of the Observable among its subscribers,
+// it is the same as Publish().RefCount().
+func (parent *Observable) Share() *Observable {
+	o := parent.Publish().RefCount()
+	o.Name = "share"
+	return o
+}
@@ -0,0 +1,111 @@
+package rxgo_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pmlpml/rxgo"
+	"github.com/stretchr/testify/assert"
+)
+
+// source emits 1, 2, 3 when the gate is open, it counts the subscriptions
+func gatedSource(gate chan bool) (*rxgo.Observable, *int32) {
+	var connected int32
+	return rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+		atomic.AddInt32(&connected, 1)
+		<-gate
+		for i := 1; i <= 3; i++ {
+			if send(i) {
+				return
+			}
+		}
+	}), &connected
+}
+
+func TestPublish(t *testing.T) {
+	gate := make(chan bool)
+	close(gate)
+	source, connected := gatedSource(gate)
+	c := source.Publish()
+	first := subscribeAsync(c.Observable)
+	second := subscribeAsync(c.Observable)
+	c.Connect()
+
+	assert.Equal(t, []interface{}{1, 2, 3}, first(), "Publish Test Error!")
+	assert.Equal(t, []interface{}{1, 2, 3}, second(), "Publish Shared Test Error!")
+	assert.Equal(t, int32(1), atomic.LoadInt32(connected), "Publish Connect Test Error!")
+
+	// a new subscription after the previous one terminated
+	third := subscribeAsync(c.Observable)
+	c.Connect()
+	assert.Equal(t, []interface{}{1, 2, 3}, third(), "Publish Reconnect Test Error!")
+	assert.Equal(t, int32(2), atomic.LoadInt32(connected), "Publish Reconnect Count Test Error!")
+}
+
+func TestConnectCancel(t *testing.T) {
+	c := rxgo.Never().Publish()
+	result := subscribeAsync(c.Map(func(x int) int {
+		return x
+	}))
+	cancel := c.Connect()
+	cancel()
+	assert.Equal(t, []interface{}{}, result(), "Connect Cancel Test Error!")
+}
+
+func TestShare(t *testing.T) {
+	gate := make(chan bool)
+	source, connected := gatedSource(gate)
+	shared := source.Share()
+	first := subscribeAsync(shared)
+	second := subscribeAsync(shared)
+	close(gate)
+
+	assert.Equal(t, []interface{}{1, 2, 3}, first(), "Share Test Error!")
+	assert.Equal(t, []interface{}{1, 2, 3}, second(), "Share Shared Test Error!")
+	assert.Equal(t, int32(1), atomic.LoadInt32(connected), "Share Connect Test Error!")
+
+	assert.Equal(t, []interface{}{1, 2, 3}, subscribeAsync(shared)(), "Share Reconnect Test Error!")
+	assert.Equal(t, int32(2), atomic.LoadInt32(connected), "Share Reconnect Count Test Error!")
+}
+
+func TestShareResubscribe(t *testing.T) {
+	// a subscriber joins the running subscription or a new one, but never a stopped one
+	shared := rxgo.Interval(5 * time.Millisecond).Share()
+	for i := 0; i < 50; i++ {
+		res := []int{}
+		shared.Take(2).Subscribe(func(x int) {
+			res = append(res, x)
+		})
+		if assert.Len(t, res, 2, "Share Resubscribe Test Error!") {
+			assert.Equal(t, res[0]+1, res[1], "Share Resubscribe Ticks Test Error!")
+		}
+	}
+}
+
+func TestRefCount(t *testing.T) {
+	var connected int32
+	stopped := make(chan bool)
+	c := rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+		atomic.AddInt32(&connected, 1)
+		<-ctx.Done()
+		close(stopped)
+	}).Publish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan bool)
+	go func() {
+		c.RefCount().Subscribe(rxgo.ObserverMonitor{
+			Context: func() context.Context {
+				return ctx
+			},
+			AfterConnected: cancel,
+		})
+		close(done)
+	}()
+	<-done
+	// the last subscriber unsubscribed, so the upstream is stopped
+	<-stopped
+	assert.Equal(t, int32(1), atomic.LoadInt32(&connected), "RefCount Test Error!")
+}
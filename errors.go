@@ -0,0 +1,308 @@
+// Copyright 2018 The SS.SYSU Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rxgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"time"
+)
+
+// Backoff gives the delay before the n-th retry, n starts from 0.
+type Backoff func(n int) time.Duration
+
+// ConstantBackoff delays each retry for d.
+func ConstantBackoff(d time.Duration) Backoff {
+	return func(n int) time.Duration {
+		return d
+	}
+}
+
+// ExponentialBackoff delays the first retry for initial, and doubles the delay for each retry until max.
+func ExponentialBackoff(initial, max time.Duration) Backoff {
+	return func(n int) time.Duration {
+		d := initial
+		for ; n > 0 && d < max; n-- {
+			d *= 2
+		}
+		if d > max {
+			d = max
+		}
+		return d
+	}
+}
+
+// JitteredBackoff delays each retry for a random duration between the half and the whole delay of b.
+func JitteredBackoff(b Backoff) Backoff {
+	return func(n int) time.Duration {
+		d := b(n)
+		if d <= 1 {
+			return d
+		}
+		return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+	}
+}
+
+// RetryPolicy decides whether and when an Observable is resubscribed after an error item.
+type RetryPolicy struct {
+	MaxRetries     int              // retries at most, unlimited if negative
+	Backoff        Backoff          // delay before each retry, no delay if nil
+	MaxElapsedTime time.Duration    // no retry is started after this budget since subscribed, unlimited if zero
+	Retryable      func(error) bool // whether the error is retryable, all errors are if nil
+}
+
+// get the delay of the n-th retry, ok is false if the error should not be retried
+func (p RetryPolicy) retry(e error, n int, start time.Time) (delay time.Duration, ok bool) {
+	if p.MaxRetries >= 0 && n >= p.MaxRetries {
+		return
+	}
+	if p.Retryable != nil && !p.Retryable(e) {
+		return
+	}
+	if p.Backoff != nil {
+		delay = p.Backoff(n)
+	}
+	if p.MaxElapsedTime > 0 && time.Since(start)+delay > p.MaxElapsedTime {
+		return
+	}
+	return delay, true
+}
+
+// Retry resubscribes the upstream Observables when an error item arrives, at most n times
+// or unlimited if n is negative. The error item is emitted if it is not retried.
+func (parent *Observable) Retry(n int) (o *Observable) {
+	o = parent.RetryWhen(RetryPolicy{MaxRetries: n})
+	o.Name = "retry"
+	return o
+}
+
+// RetryWhen resubscribes the upstream Observables by the policy when an error item arrives.
+// The upstream is stopped and connected again after the backoff delay. The error item is emitted if it is not retried.
+func (parent *Observable) RetryWhen(policy RetryPolicy) (o *Observable) {
+	o = parent.newTransformObservable("retryWhen")
+	o.flip = policy
+	o.operator = retryOperater
+	return o
+}
+
+var retryOperater = upstreamOperater{func(ctx context.Context, o *Observable, up *upstream, out chan interface{}) {
+	policy := o.flip.(RetryPolicy)
+	in := up.in
+	start := time.Now()
+	retries := 0
+	for in != nil {
+		x, ok := <-in
+		if !ok {
+			return
+		}
+		if e, ok := x.(error); ok {
+			if delay, ok := policy.retry(e, retries, start); ok {
+				retries++
+				in = up.reconnect(delay)
+				continue
+			}
+		}
+		if o.sendToFlow(ctx, x, out) {
+			return
+		}
+	}
+}}
+
+// get the matcher of error items by the target, which is nil for all errors, a func(error) bool predicate,
+// a non-nil pointer to an error type or an interface for errors.As, or an error value for errors.Is
+func errorMatcher(target interface{}) func(e error) bool {
+	switch t := target.(type) {
+	case nil:
+		return func(e error) bool {
+			return true
+		}
+	case func(error) bool:
+		return t
+	}
+
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+	tv := reflect.ValueOf(target)
+	if tv.Kind() == reflect.Ptr && !tv.IsNil() &&
+		(tv.Type().Elem().Kind() == reflect.Interface || tv.Type().Elem().Implements(errorType)) {
+		elemType := tv.Type().Elem()
+		return func(e error) bool {
+			// each match uses its own target, since the subscriptions may run concurrently
+			return errors.As(e, reflect.New(elemType).Interface())
+		}
+	}
+
+	if t, ok := target.(error); ok {
+		return func(e error) bool {
+			return errors.Is(e, t)
+		}
+	}
+	panic(ErrFuncFlip)
+}
+
+// catch flip of error recovery operators
+type catchFlip struct {
+	match   func(e error) bool
+	handler func(e error) *Observable
+}
+
+// Catch switches to the Observable returned by the handler when an error item matching the target arrives,
+// and the upstream is stopped. The target is an error value matched by errors.Is, a pointer to an error type
+// matched by errors.As, a func(error) bool predicate, or nil for all errors. FlowableError is unwrapped when matching.
+// Other error items are emitted as usual. It completes if the handler returns nil.
+func (parent *Observable) Catch(target interface{}, handler func(e error) *Observable) (o *Observable) {
+	o = parent.newTransformObservable("catch")
+	o.flip = catchFlip{errorMatcher(target), handler}
+	o.operator = catchOperater
+	return o
+}
+
+// OnErrorResumeNext switches to the Observable returned by the function when an error item arrives,
+// and the upstream is stopped. It completes if the function returns nil.
+func (parent *Observable) OnErrorResumeNext(f func(e error) *Observable) (o *Observable) {
+	o = parent.Catch(nil, f)
+	o.Name = "onErrorResumeNext"
+	return o
+}
+
+var catchOperater = upstreamOperater{func(ctx context.Context, o *Observable, up *upstream, out chan interface{}) {
+	fl := o.flip.(catchFlip)
+	for x := range up.in {
+		e, ok := x.(error)
+		if !ok || !fl.match(e) {
+			if o.sendToFlow(ctx, x, out) {
+				return
+			}
+			continue
+		}
+
+		rs, skip, stop, eo := userFuncCall(reflect.ValueOf(fl.handler), []reflect.Value{reflect.ValueOf(e)})
+		if skip {
+			continue
+		}
+		// the upstream is drained after the outflow is closed, so that a stalled one does not delay the fallback
+		up.cancel()
+		if stop {
+			return
+		}
+		if eo != nil {
+			o.sendToFlow(ctx, eo, out)
+			return
+		}
+		if next := rs[0].Interface().(*Observable); next != nil {
+			o.switchTo(ctx, next, out)
+		}
+		return
+	}
+}}
+
+// connect the next Observable and emit its items
+func (o *Observable) switchTo(ctx context.Context, next *Observable, out chan interface{}) {
+	for x := range next.connectFlow(ctx) {
+		if o.sendToFlow(ctx, x, out) {
+			return
+		}
+	}
+}
+
+// OnErrorReturn replaces each error item with the item returned by the function, and the flow goes on.
+func (parent *Observable) OnErrorReturn(f func(e error) interface{}) (o *Observable) {
+	o = parent.newTransformObservable("onErrorReturn")
+	o.flip = f
+	o.operator = onErrorReturnOperater
+	return o
+}
+
+var onErrorReturnOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	fv := reflect.ValueOf(o.flip)
+	for x := range in {
+		if e, ok := x.(error); ok {
+			rs, skip, stop, eo := userFuncCall(fv, []reflect.Value{reflect.ValueOf(e)})
+			if stop {
+				return
+			}
+			if skip {
+				continue
+			}
+			if eo != nil {
+				x = eo
+			} else {
+				x = rs[0].Interface()
+			}
+		}
+		if o.sendToFlow(ctx, x, out) {
+			return
+		}
+	}
+}}
+
+// TimeoutError is emitted by Timeout when no item arrives in time.
+type TimeoutError struct {
+	Timeout time.Duration
+}
+
+func (e TimeoutError) Error() string {
+	return fmt.Sprintf("No item in %v!", e.Timeout)
+}
+
+// timeout flip of Timeout operators
+type timeoutFlip struct {
+	timeout  time.Duration
+	fallback *Observable
+}
+
+// Timeout emits a TimeoutError and completes if no item arrives within d since the previous one or the subscription,
+// the stalled upstream is stopped.
+func (parent *Observable) Timeout(d time.Duration) (o *Observable) {
+	o = parent.newTransformObservable("timeout")
+	o.flip = timeoutFlip{timeout: d}
+	o.operator = timeoutOperater
+	return o
+}
+
+// TimeoutWithFallback switches to the other Observable if no item arrives within d since the previous one
+// or the subscription, the stalled upstream is stopped.
+func (parent *Observable) TimeoutWithFallback(d time.Duration, other *Observable) (o *Observable) {
+	o = parent.newTransformObservable("timeoutWithFallback")
+	o.flip = timeoutFlip{d, other}
+	o.operator = timeoutOperater
+	return o
+}
+
+var timeoutOperater = upstreamOperater{func(ctx context.Context, o *Observable, up *upstream, out chan interface{}) {
+	fl := o.flip.(timeoutFlip)
+	timer := time.NewTimer(fl.timeout)
+	defer func() {
+		timer.Stop()
+	}()
+
+	for {
+		select {
+		case x, ok := <-up.in:
+			if !ok {
+				return
+			}
+			// a new timer, the stopped one may have fired
+			timer.Stop()
+			timer = time.NewTimer(fl.timeout)
+			if o.sendToFlow(ctx, x, out) {
+				return
+			}
+		case <-timer.C:
+			// the upstream is drained after the outflow is closed, so that a stalled one does not delay the timeout
+			up.cancel()
+			if fl.fallback == nil {
+				o.sendToFlow(ctx, TimeoutError{fl.timeout}, out)
+				return
+			}
+			o.switchTo(ctx, fl.fallback, out)
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}}
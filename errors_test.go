@@ -0,0 +1,338 @@
+package rxgo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pmlpml/rxgo"
+	"github.com/stretchr/testify/assert"
+)
+
+// source fails the first n times it is connected
+func failingSource(n int, e error) (*rxgo.Observable, *int) {
+	connected := 0
+	return rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+		connected++
+		send(connected)
+		if connected <= n {
+			send(e)
+			send(-1)
+			return
+		}
+		send(100)
+	}), &connected
+}
+
+func TestRetry(t *testing.T) {
+	ee := errors.New("Any")
+	res := []interface{}{}
+	observer := rxgo.ObserverMonitor{
+		Next: func(x interface{}) {
+			res = append(res, x)
+		},
+		Error: func(e error) {
+			res = append(res, e)
+		},
+	}
+
+	source, connected := failingSource(2, ee)
+	source.Retry(3).Subscribe(observer)
+	assert.Equal(t, []interface{}{1, 2, 3, 100}, res, "Retry Test Error!")
+	assert.Equal(t, 3, *connected, "Retry Connect Test Error!")
+
+	res = []interface{}{}
+	source, _ = failingSource(5, ee)
+	source.Retry(1).Subscribe(observer)
+	assert.Equal(t, []interface{}{1, 2, ee, -1}, res, "Retry Exhausted Test Error!")
+}
+
+func TestRetryWhen(t *testing.T) {
+	ee := errors.New("Any")
+	fatal := errors.New("Fatal")
+	res := []interface{}{}
+	observer := rxgo.ObserverMonitor{
+		Next: func(x interface{}) {
+			res = append(res, x)
+		},
+		Error: func(e error) {
+			res = append(res, e)
+		},
+	}
+
+	source, _ := failingSource(3, ee)
+	start := time.Now()
+	source.RetryWhen(rxgo.RetryPolicy{
+		MaxRetries: -1,
+		Backoff:    rxgo.JitteredBackoff(rxgo.ExponentialBackoff(10*time.Millisecond, time.Second)),
+	}).Subscribe(observer)
+	assert.Equal(t, []interface{}{1, 2, 3, 4, 100}, res, "RetryWhen Test Error!")
+	assert.True(t, time.Since(start) >= 35*time.Millisecond, "RetryWhen Backoff Test Error!")
+
+	res = []interface{}{}
+	source, _ = failingSource(3, fatal)
+	source.RetryWhen(rxgo.RetryPolicy{
+		MaxRetries: -1,
+		Retryable: func(e error) bool {
+			return e != fatal
+		},
+	}).Subscribe(observer)
+	assert.Equal(t, []interface{}{1, fatal, -1}, res, "RetryWhen Retryable Test Error!")
+
+	res = []interface{}{}
+	source, _ = failingSource(100, ee)
+	source.RetryWhen(rxgo.RetryPolicy{
+		MaxRetries:     -1,
+		Backoff:        rxgo.ConstantBackoff(50 * time.Millisecond),
+		MaxElapsedTime: 125 * time.Millisecond,
+	}).Subscribe(observer)
+	assert.Equal(t, []interface{}{1, 2, 3, ee, -1}, res, "RetryWhen Budget Test Error!")
+}
+
+func TestBackoff(t *testing.T) {
+	b := rxgo.ExponentialBackoff(time.Millisecond, 5*time.Millisecond)
+	assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond, 5 * time.Millisecond},
+		[]time.Duration{b(0), b(1), b(2), b(10)}, "ExponentialBackoff Test Error!")
+
+	j := rxgo.JitteredBackoff(rxgo.ConstantBackoff(10 * time.Millisecond))
+	for i := 0; i < 10; i++ {
+		d := j(i)
+		assert.True(t, d >= 5*time.Millisecond && d <= 10*time.Millisecond, "JitteredBackoff Test Error!")
+	}
+}
+
+type codeError struct {
+	code int
+}
+
+func (e codeError) Error() string {
+	return "code error"
+}
+
+func TestCatch(t *testing.T) {
+	ee := errors.New("Any")
+	other := errors.New("Other")
+	res := []interface{}{}
+	observer := rxgo.ObserverMonitor{
+		Next: func(x interface{}) {
+			res = append(res, x)
+		},
+		Error: func(e error) {
+			res = append(res, e)
+		},
+	}
+
+	rxgo.Just(1, other, 2, ee, 3).Catch(ee, func(e error) *rxgo.Observable {
+		return rxgo.Just(10, 20)
+	}).Subscribe(observer)
+	assert.Equal(t, []interface{}{1, other, 2, 10, 20}, res, "Catch Is Test Error!")
+
+	res = []interface{}{}
+	rxgo.Just(1, 2, 3).Map(func(x int) int {
+		if x == 2 {
+			panic(rxgo.FlowableError{Err: codeError{7}, Elements: x})
+		}
+		return x
+	}).Catch(new(codeError), func(e error) *rxgo.Observable {
+		var ce codeError
+		errors.As(e, &ce)
+		return rxgo.Just(ce.code)
+	}).Subscribe(observer)
+	assert.Equal(t, []interface{}{1, 7}, res, "Catch As Test Error!")
+
+	res = []interface{}{}
+	rxgo.Just(1, 2, 3).FlatMap(func(x int) *rxgo.Observable {
+		if x == 2 {
+			panic(rxgo.FlowableError{Err: ee, Elements: x})
+		}
+		return rxgo.Just(x)
+	}).Catch(ee, func(e error) *rxgo.Observable {
+		return rxgo.Just(20)
+	}).Subscribe(observer)
+	assert.Equal(t, []interface{}{1, 20}, res, "Catch FlatMap Test Error!")
+
+	res = []interface{}{}
+	rxgo.Just(1, ee, 2).Catch(func(e error) bool {
+		return e == ee
+	}, func(e error) *rxgo.Observable {
+		return nil
+	}).Subscribe(observer)
+	assert.Equal(t, []interface{}{1}, res, "Catch Predicate Test Error!")
+
+	// the stalled upstream does not delay the fallback
+	start := time.Now()
+	var elapsed time.Duration
+	rxgo.Merge(rxgo.Throw(ee), rxgo.Just(1).Map(func(x int) int {
+		time.Sleep(500 * time.Millisecond)
+		return x
+	})).Catch(ee, func(e error) *rxgo.Observable {
+		return rxgo.Just(20)
+	}).Subscribe(func(x int) {
+		elapsed = time.Since(start)
+	})
+	assert.True(t, elapsed > 0 && elapsed < 250*time.Millisecond, "Catch Stalled Test Error!")
+
+	assert.Panics(t, func() {
+		rxgo.Just(1).Catch(3, nil)
+	}, "Catch Target Test Error!")
+}
+
+func TestOnErrorResumeNext(t *testing.T) {
+	ee := errors.New("Any")
+	res := []interface{}{}
+	count := 0
+	rxgo.Start(func() (interface{}, bool) {
+		count++
+		if count == 3 {
+			return ee, false
+		}
+		return count, false
+	}).OnErrorResumeNext(func(e error) *rxgo.Observable {
+		return rxgo.Just("fallback")
+	}).Subscribe(func(x interface{}) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []interface{}{1, 2, "fallback"}, res, "OnErrorResumeNext Test Error!")
+}
+
+func TestOnErrorReturn(t *testing.T) {
+	ee := errors.New("Any")
+	res := []interface{}{}
+	rxgo.Just(1, ee, 2, ee).OnErrorReturn(func(e error) interface{} {
+		return e.Error()
+	}).Subscribe(func(x interface{}) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []interface{}{1, "Any", 2, "Any"}, res, "OnErrorReturn Test Error!")
+}
+
+func TestStrictErrorMode(t *testing.T) {
+	ee := errors.New("Any")
+	res := []interface{}{}
+	completed := false
+	observer := rxgo.ObserverMonitor{
+		Next: func(x interface{}) {
+			res = append(res, x)
+		},
+		Error: func(e error) {
+			res = append(res, e)
+		},
+		Completed: func() {
+			completed = true
+		},
+	}
+
+	rxgo.Just(1, 2, ee, 3, ee).SetErrorMode(rxgo.ErrorModeStrict).Map(func(x int) int {
+		return 10 * x
+	}).Subscribe(observer)
+	assert.Equal(t, []interface{}{10, 20, ee}, res, "Strict Error Mode Test Error!")
+	assert.False(t, completed, "Strict Error Mode Completed Test Error!")
+
+	res = []interface{}{}
+	rxgo.Just(1, ee, 2).SetErrorMode(rxgo.ErrorModeStrict).SetErrorMode(rxgo.ErrorModeContinue).Subscribe(observer)
+	assert.Equal(t, []interface{}{1, ee, 2}, res, "Continue Error Mode Test Error!")
+	assert.True(t, completed, "Continue Error Mode Completed Test Error!")
+
+	// the error is delivered before the stalled upstream stops
+	start := time.Now()
+	var elapsed time.Duration
+	rxgo.Merge(rxgo.Throw(ee), rxgo.Just(1).Map(func(x int) int {
+		time.Sleep(500 * time.Millisecond)
+		return x
+	})).SetErrorMode(rxgo.ErrorModeStrict).Subscribe(rxgo.ObserverMonitor{
+		Error: func(e error) {
+			elapsed = time.Since(start)
+		},
+	})
+	assert.True(t, elapsed < 250*time.Millisecond, "Strict Error Mode Stalled Test Error!")
+
+	// the upstream is cancelled at the first error item
+	rxgo.DefaultErrorMode = rxgo.ErrorModeStrict
+	defer func() {
+		rxgo.DefaultErrorMode = rxgo.ErrorModeContinue
+	}()
+	nums := []int{}
+	count := 0
+	rxgo.Start(func() (interface{}, bool) {
+		count++
+		if count == 3 {
+			return ee, false
+		}
+		return count, false
+	}).Subscribe(func(x int) {
+		nums = append(nums, x)
+	})
+	assert.Equal(t, []int{1, 2}, nums, "Default Strict Error Mode Test Error!")
+}
+
+func TestTimeout(t *testing.T) {
+	res := []interface{}{}
+	observer := rxgo.ObserverMonitor{
+		Next: func(x interface{}) {
+			res = append(res, x)
+		},
+		Error: func(e error) {
+			var te rxgo.TimeoutError
+			if errors.As(e, &te) {
+				res = append(res, te.Timeout)
+			}
+		},
+	}
+	// the source stalls until it is stopped
+	var stopped chan bool
+	stall := func() *rxgo.Observable {
+		stopped = make(chan bool)
+		return rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+			send(1)
+			send(2)
+			<-ctx.Done()
+			close(stopped)
+		})
+	}
+
+	stall().Timeout(20 * time.Millisecond).Subscribe(observer)
+	<-stopped
+	assert.Equal(t, []interface{}{1, 2, 20 * time.Millisecond}, res, "Timeout Test Error!")
+
+	res = []interface{}{}
+	rxgo.Just(1, 2, 3).Timeout(time.Second).Subscribe(observer)
+	assert.Equal(t, []interface{}{1, 2, 3}, res, "Timeout In Time Test Error!")
+
+	res = []interface{}{}
+	stall().TimeoutWithFallback(20*time.Millisecond, rxgo.Just("a", "b")).Subscribe(observer)
+	<-stopped
+	assert.Equal(t, []interface{}{1, 2, "a", "b"}, res, "TimeoutWithFallback Test Error!")
+
+	// the stalled upstream does not delay the timeout
+	stalled := func() *rxgo.Observable {
+		return rxgo.Just(1).Map(func(x int) int {
+			time.Sleep(500 * time.Millisecond)
+			return x
+		})
+	}
+	start := time.Now()
+	var elapsed time.Duration
+	stalled().Timeout(50 * time.Millisecond).Subscribe(rxgo.ObserverMonitor{
+		Error: func(e error) {
+			elapsed = time.Since(start)
+		},
+	})
+	assert.True(t, elapsed >= 50*time.Millisecond && elapsed < 250*time.Millisecond, "Timeout Stalled Test Error!")
+
+	start = time.Now()
+	stalled().TimeoutWithFallback(50*time.Millisecond, rxgo.Just("a")).Subscribe(func(x string) {
+		elapsed = time.Since(start)
+	})
+	assert.True(t, elapsed >= 50*time.Millisecond && elapsed < 250*time.Millisecond, "TimeoutWithFallback Stalled Test Error!")
+}
+
+func TestFlowableErrorUnwrap(t *testing.T) {
+	ee := errors.New("Any")
+	var fe error = rxgo.FlowableError{Err: codeError{7}, Elements: 1}
+	var ce codeError
+	assert.True(t, errors.As(fe, &ce), "FlowableError As Test Error!")
+	assert.Equal(t, 7, ce.code, "FlowableError As Code Test Error!")
+	assert.True(t, errors.Is(rxgo.FlowableError{Err: ee}, ee), "FlowableError Is Test Error!")
+	assert.False(t, errors.Is(rxgo.FlowableError{Err: ee}, errors.New("Any")), "FlowableError Is Other Test Error!")
+}
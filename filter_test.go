@@ -0,0 +1,324 @@
+package rxgo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pmlpml/rxgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTake(t *testing.T) {
+	res := []int{}
+	count := 0
+	rxgo.Start(func() (int, bool) {
+		count++
+		return count, false
+	}).Take(3).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+
+	assert.Equal(t, []int{1, 2, 3}, res, "Take Test Error!")
+
+	res = []int{}
+	rxgo.Range(0, 5).Take(0).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []int{}, res, "Take Zero Test Error!")
+}
+
+func TestTakeLast(t *testing.T) {
+	res := []interface{}{}
+	ee := errors.New("Any")
+	rxgo.Just(1, 2, ee, 3, 4, 5).TakeLast(2).Subscribe(rxgo.ObserverMonitor{
+		Next: func(x interface{}) {
+			res = append(res, x)
+		},
+		Error: func(e error) {
+			res = append(res, e)
+		},
+	})
+
+	assert.Equal(t, []interface{}{ee, 4, 5}, res, "TakeLast Test Error!")
+
+	nums := []int{}
+	rxgo.Range(0, 5).TakeLast(-1).Subscribe(func(x int) {
+		nums = append(nums, x)
+	})
+	assert.Equal(t, []int{}, nums, "TakeLast Negative Test Error!")
+}
+
+func TestTakeWhile(t *testing.T) {
+	res := []int{}
+	rxgo.Range(0, 100).TakeWhile(func(ctx context.Context, x int) bool {
+		return x < 4
+	}).Map(func(x int) int {
+		return x * 10
+	}).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+
+	assert.Equal(t, []int{0, 10, 20, 30}, res, "TakeWhile Test Error!")
+}
+
+func TestTakeUntil(t *testing.T) {
+	res := []int{}
+	trigger := make(chan int)
+	source := make(chan int)
+	received := make(chan bool)
+	go func() {
+		source <- 1
+		source <- 2
+		<-received
+		trigger <- 0
+	}()
+
+	rxgo.From(source).TakeUntil(rxgo.From(trigger)).Subscribe(func(x int) {
+		res = append(res, x)
+		if x == 2 {
+			close(received)
+		}
+	})
+
+	assert.Equal(t, []int{1, 2}, res, "TakeUntil Test Error!")
+
+	res = []int{}
+	rxgo.Just(1, 2, 3).TakeUntil(rxgo.Never()).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []int{1, 2, 3}, res, "TakeUntil Never Test Error!")
+
+	res = []int{}
+	rxgo.Start(func() (int, bool) {
+		time.Sleep(time.Millisecond)
+		return 1, false
+	}).TakeUntil(rxgo.Just(0)).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+	assert.True(t, len(res) < 3, "TakeUntil Stop Test Error!")
+}
+
+func TestSkip(t *testing.T) {
+	res := []int{}
+	rxgo.Range(0, 6).Skip(2).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []int{2, 3, 4, 5}, res, "Skip Test Error!")
+
+	res = []int{}
+	rxgo.Range(0, 6).SkipLast(2).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []int{0, 1, 2, 3}, res, "SkipLast Test Error!")
+
+	res = []int{}
+	rxgo.Range(0, 4).SkipLast(-1).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []int{0, 1, 2, 3}, res, "SkipLast Negative Test Error!")
+}
+
+func TestSkipWhile(t *testing.T) {
+	res := []int{}
+	rxgo.Just(1, 3, 5, 6, 7, 8).SkipWhile(func(ctx context.Context, x int) bool {
+		return x%2 == 1
+	}).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+
+	assert.Equal(t, []int{6, 7, 8}, res, "SkipWhile Test Error!")
+}
+
+func TestSkipUntil(t *testing.T) {
+	res := []int{}
+	skipped := make(chan bool)
+	fired := make(chan bool)
+	// the source and the trigger are the first Observables, so each send returns after SkipUntil received it
+	source := rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+		send(1)
+		send(2)
+		close(skipped)
+		<-fired
+		send(3)
+		send(4)
+	})
+	trigger := rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+		<-skipped
+		send(0)
+		close(fired)
+	})
+
+	source.SkipUntil(trigger).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+
+	assert.Equal(t, []int{3, 4}, res, "SkipUntil Test Error!")
+}
+
+func TestFilterWithContext(t *testing.T) {
+	res := []int{}
+	rxgo.Just(0, 12, 7, 34, 2).Filter(func(ctx context.Context, x int) bool {
+		return ctx != nil && x < 10
+	}).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+
+	assert.Equal(t, []int{0, 7, 2}, res, "Filter With Context Test Error!")
+}
+
+func TestDistinct(t *testing.T) {
+	res := []interface{}{}
+	ee := errors.New("Any")
+	rxgo.Just(1, 2, ee, 1, 3, 2, ee, 4).Distinct(nil).Subscribe(rxgo.ObserverMonitor{
+		Next: func(x interface{}) {
+			res = append(res, x)
+		},
+		Error: func(e error) {
+			res = append(res, e)
+		},
+	})
+	assert.Equal(t, []interface{}{1, 2, ee, 3, ee, 4}, res, "Distinct Test Error!")
+
+	words := []string{}
+	rxgo.Just("a", "bb", "cc", "d", "eee").Distinct(func(s string) int {
+		return len(s)
+	}).SubscribeOn(rxgo.ThreadingIO).Subscribe(func(s string) {
+		words = append(words, s)
+	})
+	assert.Equal(t, []string{"a", "bb", "eee"}, words, "Distinct Key Test Error!")
+
+	res = []interface{}{}
+	rxgo.Just(1, 2).Distinct(func(x int) interface{} {
+		return struct{ v interface{} }{[]int{x}}
+	}).Subscribe(rxgo.ObserverMonitor{
+		Next: func(x interface{}) {
+			res = append(res, x)
+		},
+		Error: func(e error) {
+			res = append(res, errors.Is(e, rxgo.ErrKeyNotComparable))
+		},
+	})
+	assert.Equal(t, []interface{}{true, true}, res, "Distinct Unhashable Key Test Error!")
+}
+
+func TestDistinctWithLimit(t *testing.T) {
+	res := []int{}
+	rxgo.Just(1, 2, 3, 1, 3, 2).DistinctWithLRU(nil, 2).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []int{1, 2, 3, 1, 2}, res, "DistinctWithLRU Test Error!")
+
+	res = []int{}
+	rxgo.Start(func() func() (int, bool) {
+		i := 0
+		return func() (int, bool) {
+			if i++; i > 4 {
+				return 0, true
+			}
+			if i == 3 {
+				time.Sleep(20 * time.Millisecond)
+			}
+			return 1, false
+		}
+	}()).DistinctWithTTL(nil, 10*time.Millisecond).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []int{1, 1}, res, "DistinctWithTTL Test Error!")
+}
+
+func TestDistinctUntilChanged(t *testing.T) {
+	res := []int{}
+	rxgo.Just(1, 1, 2, 2, 2, 1, 3, 3).DistinctUntilChanged(nil, nil).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []int{1, 2, 1, 3}, res, "DistinctUntilChanged Test Error!")
+
+	res = []int{}
+	rxgo.Just(1, 12, 15, 7, 21, 25).DistinctUntilChanged(func(x int) int {
+		return x / 10
+	}, func(ctx context.Context, a, b int) bool {
+		return a == b
+	}).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []int{1, 12, 7, 21}, res, "DistinctUntilChanged Key Test Error!")
+}
+
+// source emits bursts of items separated by the gap
+func burstSource(gap time.Duration, bursts ...[]int) *rxgo.Observable {
+	return rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+		for i, burst := range bursts {
+			if i > 0 {
+				time.Sleep(gap)
+			}
+			for _, x := range burst {
+				send(x)
+			}
+		}
+	})
+}
+
+func TestDebounce(t *testing.T) {
+	res := []int{}
+	burstSource(60*time.Millisecond, []int{1, 2, 3}, []int{4, 5}).Debounce(20 * time.Millisecond).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []int{3, 5}, res, "Debounce Test Error!")
+
+	res = []int{}
+	burstSource(60*time.Millisecond, []int{1, 2, 3}, []int{4, 5}).Debounce(20 * time.Millisecond).SetPendingMode(rxgo.PendingDrop).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []int{3}, res, "Debounce Drop Test Error!")
+}
+
+func TestThrottleFirst(t *testing.T) {
+	res := []int{}
+	burstSource(60*time.Millisecond, []int{1, 2, 3}, []int{4, 5}).ThrottleFirst(30 * time.Millisecond).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []int{1, 4}, res, "ThrottleFirst Test Error!")
+}
+
+func TestSample(t *testing.T) {
+	res := []int{}
+	burstSource(60*time.Millisecond, []int{1, 2}, []int{3}).Sample(20 * time.Millisecond).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []int{2, 3}, res, "Sample Test Error!")
+
+	res = []int{}
+	burstSource(60*time.Millisecond, []int{1, 2}, []int{3}).ThrottleLast(20 * time.Millisecond).SetPendingMode(rxgo.PendingDrop).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []int{2}, res, "ThrottleLast Drop Test Error!")
+}
+
+func TestSampleWith(t *testing.T) {
+	res := []int{}
+	sampled := make(chan bool)
+	ticked := make(chan bool)
+	// the source and the sampler are the first Observables, so each send returns after SampleWith received it
+	source := rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+		send(1)
+		send(2)
+		close(sampled)
+		<-ticked
+		send(3)
+		send(4)
+	})
+	sampler := rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+		<-sampled
+		send(0)
+		send(0)
+		close(ticked)
+		<-ctx.Done()
+	})
+
+	source.SampleWith(sampler).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []int{2, 4}, res, "SampleWith Test Error!")
+}
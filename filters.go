@@ -0,0 +1,714 @@
+// Copyright 2018 The SS.SYSU Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rxgo
+
+import (
+	"container/list"
+	"context"
+	"reflect"
+	"time"
+)
+
+// Take emits only the first n items emitted by an Observable, then stops the upstream.
+func (parent *Observable) Take(n int) (o *Observable) {
+	o = parent.newTransformObservable("take")
+	o.flip = n
+	o.operator = takeOperater
+	return o
+}
+
+var takeOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	n := o.flip.(int)
+	if n <= 0 {
+		return
+	}
+	for x := range in {
+		if forwarded, end := o.forwardError(ctx, x, out); forwarded {
+			if end {
+				return
+			}
+			continue
+		}
+		if o.sendToFlow(ctx, x, out) {
+			return
+		}
+		if n--; n == 0 {
+			return
+		}
+	}
+}}
+
+// TakeLast emits only the last n items emitted by an Observable when it completes.
+func (parent *Observable) TakeLast(n int) (o *Observable) {
+	o = parent.newTransformObservable("takeLast")
+	o.flip = n
+	o.operator = takeLastOperater
+	return o
+}
+
+var takeLastOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	n := o.flip.(int)
+	if n < 0 {
+		n = 0
+	}
+	buf := make([]interface{}, 0, n)
+	for x := range in {
+		if forwarded, end := o.forwardError(ctx, x, out); forwarded {
+			if end {
+				return
+			}
+			continue
+		}
+		if n <= 0 {
+			continue
+		}
+		if len(buf) == n {
+			buf = buf[1:]
+		}
+		buf = append(buf, x)
+	}
+	for _, x := range buf {
+		if o.sendToFlow(ctx, x, out) {
+			return
+		}
+	}
+}}
+
+// TakeWhile `func(x anytype) bool` emits items emitted by an Observable as long as the
+// condition is true, then stops the upstream.
+func (parent *Observable) TakeWhile(f interface{}) (o *Observable) {
+	// check validation of f
+	fv := reflect.ValueOf(f)
+	inType := []reflect.Type{typeAny}
+	outType := []reflect.Type{typeBool}
+	b, ctx_sup := checkFuncUpcast(fv, inType, outType, true)
+	if !b {
+		panic(ErrFuncFlip)
+	}
+
+	o = parent.newTransformObservable("takeWhile")
+	o.flip_accept_error = checkFuncAcceptError(fv)
+
+	o.flip_sup_ctx = ctx_sup
+	o.flip = fv.Interface()
+	o.operator = takeWhileOperater
+	return o
+}
+
+var takeWhileOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	fv := reflect.ValueOf(o.flip)
+	for x := range in {
+		if forwarded, end := o.forwardError(ctx, x, out); forwarded {
+			if end {
+				return
+			}
+			continue
+		}
+		rs, skip, stop, e := userFuncCallWithContext(ctx, fv, o.flip_sup_ctx, reflect.ValueOf(x))
+		if stop {
+			return
+		}
+		if skip {
+			continue
+		}
+		if e != nil {
+			if o.sendToFlow(ctx, e, out) {
+				return
+			}
+			continue
+		}
+		if !rs[0].Bool() {
+			return
+		}
+		if o.sendToFlow(ctx, x, out) {
+			return
+		}
+	}
+}}
+
+// TakeUntil emits items emitted by an Observable until the other Observable emits an item,
+// then stops both of them.
+func (parent *Observable) TakeUntil(other *Observable) (o *Observable) {
+	o = parent.newTransformObservable("takeUntil")
+	o.flip = other
+	o.operator = takeUntilOperater
+	return o
+}
+
+var takeUntilOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	octx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	signal := o.flip.(*Observable).connectFlow(octx)
+
+	for {
+		select {
+		case x, ok := <-in:
+			if !ok {
+				return
+			}
+			if o.sendToFlow(ctx, x, out) {
+				return
+			}
+		case _, ok := <-signal:
+			if ok {
+				return
+			}
+			// the other Observable completed without emitting
+			signal = nil
+		}
+	}
+}}
+
+// Skip suppresses the first n items emitted by an Observable.
+func (parent *Observable) Skip(n int) (o *Observable) {
+	o = parent.newTransformObservable("skip")
+	o.flip = n
+	o.operator = skipOperater
+	return o
+}
+
+var skipOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	n := o.flip.(int)
+	for x := range in {
+		if forwarded, end := o.forwardError(ctx, x, out); forwarded {
+			if end {
+				return
+			}
+			continue
+		}
+		if n > 0 {
+			n--
+			continue
+		}
+		if o.sendToFlow(ctx, x, out) {
+			return
+		}
+	}
+}}
+
+// SkipLast suppresses the last n items emitted by an Observable.
+func (parent *Observable) SkipLast(n int) (o *Observable) {
+	o = parent.newTransformObservable("skipLast")
+	o.flip = n
+	o.operator = skipLastOperater
+	return o
+}
+
+var skipLastOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	n := o.flip.(int)
+	if n < 0 {
+		n = 0
+	}
+	buf := make([]interface{}, 0, n)
+	for x := range in {
+		if forwarded, end := o.forwardError(ctx, x, out); forwarded {
+			if end {
+				return
+			}
+			continue
+		}
+		if n <= 0 {
+			if o.sendToFlow(ctx, x, out) {
+				return
+			}
+			continue
+		}
+		// delay items by n, they are emitted when n items arrived after them
+		if len(buf) == n {
+			if o.sendToFlow(ctx, buf[0], out) {
+				return
+			}
+			buf = buf[1:]
+		}
+		buf = append(buf, x)
+	}
+}}
+
+// SkipWhile `func(x anytype) bool` suppresses items emitted by an Observable until the
+// condition becomes false, then emits all the rest items.
+func (parent *Observable) SkipWhile(f interface{}) (o *Observable) {
+	// check validation of f
+	fv := reflect.ValueOf(f)
+	inType := []reflect.Type{typeAny}
+	outType := []reflect.Type{typeBool}
+	b, ctx_sup := checkFuncUpcast(fv, inType, outType, true)
+	if !b {
+		panic(ErrFuncFlip)
+	}
+
+	o = parent.newTransformObservable("skipWhile")
+	o.flip_accept_error = checkFuncAcceptError(fv)
+
+	o.flip_sup_ctx = ctx_sup
+	o.flip = fv.Interface()
+	o.operator = skipWhileOperater
+	return o
+}
+
+var skipWhileOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	fv := reflect.ValueOf(o.flip)
+	skipping := true
+	for x := range in {
+		if skipping {
+			if forwarded, end := o.forwardError(ctx, x, out); forwarded {
+				if end {
+					return
+				}
+				continue
+			}
+			rs, skip, stop, e := userFuncCallWithContext(ctx, fv, o.flip_sup_ctx, reflect.ValueOf(x))
+			if stop {
+				return
+			}
+			if skip {
+				continue
+			}
+			if e != nil {
+				if o.sendToFlow(ctx, e, out) {
+					return
+				}
+				continue
+			}
+			if skipping = rs[0].Bool(); skipping {
+				continue
+			}
+		}
+		if o.sendToFlow(ctx, x, out) {
+			return
+		}
+	}
+}}
+
+// SkipUntil suppresses items emitted by an Observable until the other Observable emits an item.
+func (parent *Observable) SkipUntil(other *Observable) (o *Observable) {
+	o = parent.newTransformObservable("skipUntil")
+	o.flip = other
+	o.operator = skipUntilOperater
+	return o
+}
+
+var skipUntilOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	octx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	signal := o.flip.(*Observable).connectFlow(octx)
+
+	for signal != nil {
+		select {
+		case x, ok := <-in:
+			if !ok {
+				return
+			}
+			if forwarded, end := o.forwardError(ctx, x, out); forwarded && end {
+				return
+			}
+		case _, ok := <-signal:
+			if !ok {
+				// the other Observable completed without emitting, nothing will be emitted
+				return
+			}
+			signal = nil
+		}
+	}
+	// the other Observable is not needed any more
+	cancel()
+	for x := range in {
+		if o.sendToFlow(ctx, x, out) {
+			return
+		}
+	}
+}}
+
+// Distinct `func(x anytype) anytype` suppresses items whose key has been emitted before.
+// The key is the item itself if f is nil.
+func (parent *Observable) Distinct(f interface{}) (o *Observable) {
+	return parent.newDistinctObservable("distinct", f, 0, 0)
+}
+
+// DistinctWithLRU is Distinct that only remembers the latest size keys.
+func (parent *Observable) DistinctWithLRU(f interface{}, size int) (o *Observable) {
+	return parent.newDistinctObservable("distinctWithLRU", f, size, 0)
+}
+
+// DistinctWithTTL is Distinct that forgets keys not seen within ttl.
+func (parent *Observable) DistinctWithTTL(f interface{}, ttl time.Duration) (o *Observable) {
+	return parent.newDistinctObservable("distinctWithTTL", f, 0, ttl)
+}
+
+// flip of distinct operators
+type distinctFlip struct {
+	key       reflect.Value // the key selector, it is invalid if not given
+	key_ctx   bool
+	equal     reflect.Value // the key comparer, it is invalid if not given
+	equal_ctx bool
+	size      int
+	ttl       time.Duration
+}
+
+// check validation of the key selector `func(x anytype) anytype`
+func (df *distinctFlip) setKeyFunc(f interface{}) {
+	if f == nil {
+		return
+	}
+	fv := reflect.ValueOf(f)
+	inType := []reflect.Type{typeAny}
+	outType := []reflect.Type{typeAny}
+	b, ctx_sup := checkFuncUpcast(fv, inType, outType, true)
+	if !b {
+		panic(ErrFuncFlip)
+	}
+	df.key, df.key_ctx = fv, ctx_sup
+}
+
+// select the key of x, it is x itself if the key selector is not given
+func (df *distinctFlip) keyOf(ctx context.Context, x interface{}) (key interface{}, skip, stop bool, e error) {
+	if !df.key.IsValid() {
+		return x, false, false, nil
+	}
+	rs, skip, stop, e := userFuncCallWithContext(ctx, df.key, df.key_ctx, reflect.ValueOf(x))
+	if skip || stop || e != nil {
+		return
+	}
+	return rs[0].Interface(), false, false, nil
+}
+
+// select the key of x to be used in maps, it is an ErrKeyNotComparable error if the key is not hashable.
+// The value is checked rather than the type, since interfaces in the key may hold unhashable values
+func (df *distinctFlip) mapKeyOf(ctx context.Context, x interface{}) (key interface{}, skip, stop bool, e error) {
+	key, skip, stop, e = df.keyOf(ctx, x)
+	if e == nil && !skip && !stop && key != nil && !reflect.ValueOf(key).Comparable() {
+		e = FlowableError{Err: ErrKeyNotComparable, Elements: x}
+	}
+	return
+}
+
+func (parent *Observable) newDistinctObservable(name string, f interface{}, size int, ttl time.Duration) (o *Observable) {
+	df := &distinctFlip{size: size, ttl: ttl}
+	df.setKeyFunc(f)
+
+	o = parent.newTransformObservable(name)
+	o.flip_accept_error = checkFuncAcceptError(df.key)
+	o.flip = df
+	o.operator = distinctOperater
+	return o
+}
+
+var distinctOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	df := o.flip.(*distinctFlip)
+	seen := newSeenKeys(df.size, df.ttl)
+	for x := range in {
+		if forwarded, end := o.forwardError(ctx, x, out); forwarded {
+			if end {
+				return
+			}
+			continue
+		}
+		key, skip, stop, e := df.mapKeyOf(ctx, x)
+		if stop {
+			return
+		}
+		if skip {
+			continue
+		}
+		if e != nil {
+			if o.sendToFlow(ctx, e, out) {
+				return
+			}
+			continue
+		}
+		if seen.add(key, time.Now()) {
+			if o.sendToFlow(ctx, x, out) {
+				return
+			}
+		}
+	}
+}}
+
+// keys have been seen, the least recently seen ones are evicted when the size or the ttl is limited
+type seenKeys struct {
+	size  int
+	ttl   time.Duration
+	keys  map[interface{}]*list.Element
+	order *list.List // of *seenKey, the front one is the most recently seen
+}
+
+type seenKey struct {
+	key interface{}
+	at  time.Time
+}
+
+func newSeenKeys(size int, ttl time.Duration) *seenKeys {
+	return &seenKeys{size: size, ttl: ttl, keys: make(map[interface{}]*list.Element), order: list.New()}
+}
+
+// add the key and returns true if it is not seen
+func (s *seenKeys) add(key interface{}, now time.Time) bool {
+	if s.ttl > 0 {
+		for e := s.order.Back(); e != nil && now.Sub(e.Value.(*seenKey).at) >= s.ttl; e = s.order.Back() {
+			s.remove(e)
+		}
+	}
+	if e, ok := s.keys[key]; ok {
+		e.Value.(*seenKey).at = now
+		s.order.MoveToFront(e)
+		return false
+	}
+	s.keys[key] = s.order.PushFront(&seenKey{key, now})
+	if s.size > 0 && s.order.Len() > s.size {
+		s.remove(s.order.Back())
+	}
+	return true
+}
+
+func (s *seenKeys) remove(e *list.Element) {
+	delete(s.keys, e.Value.(*seenKey).key)
+	s.order.Remove(e)
+}
+
+// DistinctUntilChanged suppresses items whose key is equal to the key of their immediate predecessor.
+// The key selector is `func(x anytype) anytype` and the comparer is `func(a, b anytype) bool`,
+// the key is the item itself if keyFunc is nil, and keys are compared by reflect.DeepEqual if equalFunc is nil.
+func (parent *Observable) DistinctUntilChanged(keyFunc, equalFunc interface{}) (o *Observable) {
+	df := &distinctFlip{}
+	df.setKeyFunc(keyFunc)
+	if equalFunc != nil {
+		fv := reflect.ValueOf(equalFunc)
+		inType := []reflect.Type{typeAny, typeAny}
+		outType := []reflect.Type{typeBool}
+		b, ctx_sup := checkFuncUpcast(fv, inType, outType, true)
+		if !b {
+			panic(ErrFuncFlip)
+		}
+		df.equal, df.equal_ctx = fv, ctx_sup
+	}
+
+	o = parent.newTransformObservable("distinctUntilChanged")
+	o.flip_accept_error = checkFuncAcceptError(df.key)
+	o.flip = df
+	o.operator = distinctUntilChangedOperater
+	return o
+}
+
+var distinctUntilChangedOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	df := o.flip.(*distinctFlip)
+	var last interface{}
+	has_last := false
+	for x := range in {
+		if forwarded, end := o.forwardError(ctx, x, out); forwarded {
+			if end {
+				return
+			}
+			continue
+		}
+		key, skip, stop, e := df.keyOf(ctx, x)
+		changed := true
+		if e == nil && !skip && !stop && has_last {
+			changed, skip, stop, e = df.changed(ctx, last, key)
+		}
+		if stop {
+			return
+		}
+		if skip {
+			continue
+		}
+		if e != nil {
+			if o.sendToFlow(ctx, e, out) {
+				return
+			}
+			continue
+		}
+		last, has_last = key, true
+		if changed {
+			if o.sendToFlow(ctx, x, out) {
+				return
+			}
+		}
+	}
+}}
+
+// compare keys by the comparer
+func (df *distinctFlip) changed(ctx context.Context, a, b interface{}) (changed, skip, stop bool, e error) {
+	if !df.equal.IsValid() {
+		return !reflect.DeepEqual(a, b), false, false, nil
+	}
+	rs, skip, stop, e := userFuncCallWithContext(ctx, df.equal, df.equal_ctx, reflect.ValueOf(a), reflect.ValueOf(b))
+	if skip || stop || e != nil {
+		return
+	}
+	return !rs[0].Bool(), false, false, nil
+}
+
+// PendingMode decides whether Debounce, ThrottleLast, Sample and SampleWith emit or drop
+// the pending item when the upstream completes.
+type PendingMode uint
+
+const (
+	PendingFlush PendingMode = iota // emit the pending item before completion, the default
+	PendingDrop                     // drop the pending item
+)
+
+// SetPendingMode sets the mode of the pending item when the upstream completes.
+func (o *Observable) SetPendingMode(mode PendingMode) *Observable {
+	o.pending_mode = mode
+	return o
+}
+
+// emit the pending item when the upstream completes
+func (o *Observable) flushPending(ctx context.Context, pending interface{}, has bool, out chan interface{}) {
+	if has && o.pending_mode == PendingFlush {
+		o.sendToFlow(ctx, pending, out)
+	}
+}
+
+// Debounce emits an item only if d has passed without another item, error items are emitted at once.
+func (parent *Observable) Debounce(d time.Duration) (o *Observable) {
+	o = parent.newTransformObservable("debounce")
+	o.flip = d
+	o.operator = debounceOperater
+	return o
+}
+
+var debounceOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	d := o.flip.(time.Duration)
+	timer := time.NewTimer(d)
+	timer.Stop()
+	defer func() {
+		timer.Stop()
+	}()
+
+	var pending interface{}
+	var fire <-chan time.Time
+	for {
+		select {
+		case x, ok := <-in:
+			if !ok {
+				o.flushPending(ctx, pending, fire != nil, out)
+				return
+			}
+			if forwarded, end := o.forwardError(ctx, x, out); forwarded {
+				if end {
+					return
+				}
+				continue
+			}
+			// a new timer, the stopped one may have fired
+			timer.Stop()
+			timer = time.NewTimer(d)
+			pending, fire = x, timer.C
+		case <-fire:
+			fire = nil
+			if o.sendToFlow(ctx, pending, out) {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}}
+
+// ThrottleFirst emits the first item and then ignores items for d, error items are emitted at once.
+func (parent *Observable) ThrottleFirst(d time.Duration) (o *Observable) {
+	o = parent.newTransformObservable("throttleFirst")
+	o.flip = d
+	o.operator = throttleFirstOperater
+	return o
+}
+
+var throttleFirstOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	d := o.flip.(time.Duration)
+	var last time.Time
+	for x := range in {
+		if forwarded, end := o.forwardError(ctx, x, out); forwarded {
+			if end {
+				return
+			}
+			continue
+		}
+		if now := time.Now(); last.IsZero() || now.Sub(last) >= d {
+			last = now
+			if o.sendToFlow(ctx, x, out) {
+				return
+			}
+		}
+	}
+}}
+
+// ThrottleLast emits the latest item in each period of d, it is the same as Sample.
+func (parent *Observable) ThrottleLast(d time.Duration) (o *Observable) {
+	o = parent.Sample(d)
+	o.Name = "throttleLast"
+	return o
+}
+
+// Sample emits the latest item in each period of d if there is a new one, error items are emitted at once.
+func (parent *Observable) Sample(d time.Duration) (o *Observable) {
+	o = parent.newTransformObservable("sample")
+	o.flip = d
+	o.operator = sampleOperater
+	return o
+}
+
+// SampleWith emits the latest item when the other Observable emits an item if there is a new one,
+// and it completes when the other Observable completes. Error items are emitted at once.
+func (parent *Observable) SampleWith(other *Observable) (o *Observable) {
+	o = parent.newTransformObservable("sampleWith")
+	o.flip = other
+	o.operator = sampleOperater
+	return o
+}
+
+var sampleOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	var tick <-chan time.Time
+	var signal chan interface{}
+	switch fl := o.flip.(type) {
+	case time.Duration:
+		ticker := time.NewTicker(fl)
+		defer ticker.Stop()
+		tick = ticker.C
+	case *Observable:
+		sctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		signal = fl.connectFlow(sctx)
+	}
+
+	var pending interface{}
+	has := false
+	emit := func() (end bool) {
+		if !has {
+			return false
+		}
+		has = false
+		return o.sendToFlow(ctx, pending, out)
+	}
+	for {
+		select {
+		case x, ok := <-in:
+			if !ok {
+				o.flushPending(ctx, pending, has, out)
+				return
+			}
+			if forwarded, end := o.forwardError(ctx, x, out); forwarded {
+				if end {
+					return
+				}
+				continue
+			}
+			pending, has = x, true
+		case <-tick:
+			if emit() {
+				return
+			}
+		case _, ok := <-signal:
+			if !ok {
+				o.flushPending(ctx, pending, has, out)
+				return
+			}
+			if emit() {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}}
@@ -7,6 +7,7 @@ package rxgo
 import (
 	"context"
 	"reflect"
+	"time"
 )
 
 // source node implementation of streamOperator
@@ -258,6 +259,129 @@ func Throw(e error) *Observable {
 	return o
 }
 
+var deferSource = rangeSource
+
+// Defer creates an Observable that calls the factory to build a fresh Observable each time it is connected,
+// and emits the items of the new one. It emits nothing if the factory returns nil.
+func Defer(factory func() *Observable) *Observable {
+	o := newGeneratorObservable("Defer")
+
+	o.flip = func(ctx context.Context, out chan interface{}) {
+		source := factory()
+		if source == nil {
+			return
+		}
+		for x := range source.connectFlow(ctx) {
+			if o.sendToFlow(ctx, x, out) {
+				return
+			}
+		}
+	}
+	o.operator = deferSource
+	return o
+}
+
+// Clock provides the time of time-driven sources, it can be replaced by a fake clock for deterministic tests.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) ClockTimer
+}
+
+// ClockTimer is a timer created by a Clock, the channel receives the time when it fires.
+type ClockTimer interface {
+	Chan() <-chan time.Time
+	Stop() bool
+}
+
+// SystemClock is the default Clock based on the time package.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTimer(d time.Duration) ClockTimer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+type systemTimer struct {
+	*time.Timer
+}
+
+func (t systemTimer) Chan() <-chan time.Time {
+	return t.C
+}
+
+// SetClock sets the clock of time-driven sources, such as Interval and Timer.
+func (o *Observable) SetClock(clock Clock) *Observable {
+	o.clock = clock
+	return o
+}
+
+func (o *Observable) getClock() Clock {
+	if o.clock == nil {
+		return SystemClock
+	}
+	return o.clock
+}
+
+// wait on the clock until t, returns false if ctx is done
+func waitUntil(ctx context.Context, clock Clock, t time.Time) bool {
+	timer := clock.NewTimer(t.Sub(clock.Now()))
+	defer timer.Stop()
+	select {
+	case <-timer.Chan():
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+var intervalSource = rangeSource
+var timerSource = rangeSource
+
+// Interval creates an Observable that emits the tick counters 0, 1, 2 ... every period.
+func Interval(period time.Duration) *Observable {
+	o := IntervalWithInitialDelay(period, period)
+	o.Name = "Interval"
+	return o
+}
+
+// IntervalWithInitialDelay creates an Observable that emits the tick counter 0 after the delay,
+// then 1, 2 ... every period. The ticks are scheduled at a fixed rate, and a slow observer delays them.
+func IntervalWithInitialDelay(delay, period time.Duration) *Observable {
+	o := newGeneratorObservable("IntervalWithInitialDelay")
+
+	o.flip = func(ctx context.Context, out chan interface{}) {
+		clock := o.getClock()
+		next := clock.Now().Add(delay)
+		for i := 0; waitUntil(ctx, clock, next); i++ {
+			if o.sendToFlow(ctx, i, out) {
+				return
+			}
+			next = next.Add(period)
+		}
+	}
+	o.operator = intervalSource
+	return o
+}
+
+// Timer creates an Observable that emits the tick counter 0 after the delay and completes.
+func Timer(delay time.Duration) *Observable {
+	o := newGeneratorObservable("Timer")
+
+	o.flip = func(ctx context.Context, out chan interface{}) {
+		clock := o.getClock()
+		if waitUntil(ctx, clock, clock.Now().Add(delay)) {
+			o.sendToFlow(ctx, 0, out)
+		}
+	}
+	o.operator = timerSource
+	return o
+}
+
 func newGeneratorObservable(name string) (o *Observable) {
 	//new Observable
 	o = newObservable()
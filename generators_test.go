@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -186,3 +187,138 @@ func TestNeverWithCancel(t *testing.T) {
 
 	rxgo.Never().Subscribe(oberver)
 }
+
+// fakeClock fires the earliest timer when it is advanced
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	timers  []*fakeTimer
+	waiting chan bool // notified when a timer is created
+}
+
+type fakeTimer struct {
+	clock *fakeClock
+	at    time.Time
+	c     chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(0, 0), waiting: make(chan bool, 16)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) rxgo.ClockTimer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{c, c.now.Add(d), make(chan time.Time, 1)}
+	c.timers = append(c.timers, t)
+	c.waiting <- true
+	return t
+}
+
+// wait for a timer and advance to it
+func (c *fakeClock) advance() {
+	<-c.waiting
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := c.timers[0]
+	c.timers = c.timers[1:]
+	if t.at.After(c.now) {
+		c.now = t.at
+	}
+	t.c <- c.now
+}
+
+func (t *fakeTimer) Chan() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	for i, x := range t.clock.timers {
+		if x == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func TestInterval(t *testing.T) {
+	clock := newFakeClock()
+	res := []time.Duration{}
+	received := make(chan bool)
+	done := make(chan bool)
+	go func() {
+		rxgo.IntervalWithInitialDelay(5*time.Second, time.Second).SetClock(clock).Take(3).Subscribe(func(x int) {
+			res = append(res, time.Duration(x)*time.Hour+clock.Now().Sub(time.Unix(0, 0)))
+			received <- true
+		})
+		close(done)
+	}()
+	for i := 0; i < 3; i++ {
+		clock.advance()
+		<-received
+	}
+	<-done
+	assert.Equal(t, []time.Duration{5 * time.Second, time.Hour + 6*time.Second, 2*time.Hour + 7*time.Second}, res, "Interval Test Error!")
+
+	ticks := []int{}
+	rxgo.Interval(time.Millisecond).Take(3).Subscribe(func(x int) {
+		ticks = append(ticks, x)
+	})
+	assert.Equal(t, []int{0, 1, 2}, ticks, "Interval System Clock Test Error!")
+}
+
+func TestTimer(t *testing.T) {
+	clock := newFakeClock()
+	res := []time.Duration{}
+	done := make(chan bool)
+	go func() {
+		rxgo.Timer(3 * time.Second).SetClock(clock).Subscribe(func(x int) {
+			res = append(res, time.Duration(x)+clock.Now().Sub(time.Unix(0, 0)))
+		})
+		close(done)
+	}()
+	clock.advance()
+	<-done
+	assert.Equal(t, []time.Duration{3 * time.Second}, res, "Timer Test Error!")
+}
+
+func TestDefer(t *testing.T) {
+	fibonacci := func() *rxgo.Observable {
+		a, b := 0, 1
+		return rxgo.Start(func() (int, bool) {
+			if a > 10 {
+				return 0, true
+			}
+			a, b = b, a+b
+			return b - a, false
+		})
+	}
+	source := rxgo.Defer(fibonacci).Map(func(x int) int {
+		return 2 * x
+	})
+
+	for i := 0; i < 2; i++ {
+		res := []int{}
+		source.Subscribe(func(x int) {
+			res = append(res, x)
+		})
+		assert.Equal(t, []int{0, 2, 2, 4, 6, 10, 16}, res, "Defer Test Error!")
+	}
+
+	res := []int{}
+	rxgo.Defer(func() *rxgo.Observable {
+		return nil
+	}).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []int{}, res, "Defer Nil Test Error!")
+}
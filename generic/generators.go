@@ -0,0 +1,77 @@
+// Copyright 2018 The SS.SYSU Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package generic
+
+import (
+	"context"
+)
+
+// Generator creates an Observable[T] with a source function. The send returns true when
+// the source should stop.
+func Generator[T any](sf func(ctx context.Context, send func(x T) (endSignal bool))) *Observable[T] {
+	o := newObservable[T]("CustomSource", 0)
+	o.connect = func(ctx context.Context) <-chan item[T] {
+		out := make(chan item[T], o.buf_len)
+		go func() {
+			defer close(out)
+			sf(ctx, func(x T) bool {
+				return sendToFlow(ctx, item[T]{v: x}, out)
+			})
+		}()
+		return out
+	}
+	return o
+}
+
+// Just creates an Observable[T] with the provided item(s).
+func Just[T any](items ...T) *Observable[T] {
+	o := From(items)
+	o.Name = "Just"
+	return o
+}
+
+// From creates an Observable[T] that emits items of a slice.
+func From[T any](items []T) *Observable[T] {
+	o := Generator(func(ctx context.Context, send func(x T) (endSignal bool)) {
+		for _, x := range items {
+			if send(x) {
+				return
+			}
+		}
+	})
+	o.Name = "From Slice"
+	return o
+}
+
+// FromChannel creates an Observable[T] that emits items received from a channel until it is closed.
+func FromChannel[T any](ch <-chan T) *Observable[T] {
+	o := Generator(func(ctx context.Context, send func(x T) (endSignal bool)) {
+		for {
+			select {
+			case x, ok := <-ch:
+				if !ok || send(x) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+	o.Name = "From Channel"
+	return o
+}
+
+// Range creates an Observable[int] that emits a particular range of sequential integers.
+func Range(start, end int) *Observable[int] {
+	o := Generator(func(ctx context.Context, send func(x int) (endSignal bool)) {
+		for i := start; i < end; i++ {
+			if send(i) {
+				return
+			}
+		}
+	})
+	o.Name = "Range"
+	return o
+}
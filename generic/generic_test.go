@@ -0,0 +1,110 @@
+package generic_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/pmlpml/rxgo"
+	"github.com/pmlpml/rxgo/generic"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMap(t *testing.T) {
+	res := []string{}
+	generic.Map(generic.Just(10, 20, 30), func(x int) string {
+		return strconv.Itoa(2 * x)
+	}).SubscribeFunc(func(x string) {
+		res = append(res, x)
+	})
+
+	assert.Equal(t, []string{"20", "40", "60"}, res, "Map Test Error!")
+}
+
+func TestFilter(t *testing.T) {
+	res := []int{}
+	generic.Filter(generic.Range(0, 10), func(x int) bool {
+		if x == 5 {
+			panic(rxgo.ErrSkipItem)
+		}
+		if x == 8 {
+			panic(rxgo.ErrEoFlow)
+		}
+		return x%2 == 1
+	}).SubscribeFunc(func(x int) {
+		res = append(res, x)
+	})
+
+	assert.Equal(t, []int{1, 3, 7}, res, "Filter Test Error!")
+}
+
+func TestFlatMap(t *testing.T) {
+	res := []int{}
+	generic.FlatMap(generic.Just(10, 20, 30), func(x int) *generic.Observable[int] {
+		return generic.Just(x+1, x+2)
+	}).SubscribeFunc(func(x int) {
+		res = append(res, x)
+	})
+
+	assert.Equal(t, []int{11, 12, 21, 22, 31, 32}, res, "FlatMap Test Error!")
+}
+
+func TestFromChannel(t *testing.T) {
+	ch := make(chan int)
+	go func() {
+		ch <- 10
+		ch <- 20
+		close(ch)
+	}()
+
+	res := []int{}
+	generic.FromChannel(ch).SubscribeFunc(func(x int) {
+		res = append(res, x)
+	})
+
+	assert.Equal(t, []int{10, 20}, res, "FromChannel Test Error!")
+}
+
+func TestGeneratorWithCancel(t *testing.T) {
+	res := []int{}
+	ctx, cancel := context.WithCancel(context.Background())
+	generic.Generator(func(ctx context.Context, send func(x int) (endSignal bool)) {
+		for i := 0; !send(i); i++ {
+		}
+	}).Subscribe(generic.ObserverMonitor[int]{
+		Next: func(x int) {
+			res = append(res, x)
+			if x >= 3 {
+				cancel()
+			}
+		},
+		Context: func() context.Context {
+			return ctx
+		},
+	})
+
+	assert.False(t, len(res) > 5, "Generator cancel failure!")
+}
+
+func TestConversion(t *testing.T) {
+	ee := errors.New("Any")
+	res := []interface{}{}
+	ro := rxgo.Just(1, "two", ee, 3)
+	generic.Map(generic.FromObservable[int](ro), func(x int) int {
+		return 10 * x
+	}).ToObservable().Subscribe(rxgo.ObserverMonitor{
+		Next: func(x interface{}) {
+			res = append(res, x)
+		},
+		Error: func(e error) {
+			if fe, ok := e.(rxgo.FlowableError); ok && fe.Err == generic.ErrItemType {
+				res = append(res, "type")
+			} else {
+				res = append(res, e)
+			}
+		},
+	})
+
+	assert.Equal(t, []interface{}{10, "type", ee, 30}, res, "Conversion Test Error!")
+}
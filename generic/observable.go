@@ -0,0 +1,191 @@
+// Copyright 2018 The SS.SYSU Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package generic provides a type-safe Observable[T] alongside the reflection-based rxgo.Observable.
+// Functions of operators are checked by the compiler and called without reflection.
+package generic
+
+import (
+	"context"
+	"errors"
+
+	"github.com/pmlpml/rxgo"
+)
+
+// an item of rxgo.Observable can not be converted to the type of Observable[T]
+var ErrItemType = errors.New("Item type mismatch")
+
+// default buffer of channels
+var BufferLen uint = 128
+
+// Observer[T] subscribes to an Observable[T].
+type Observer[T any] interface {
+	OnNext(x T)
+	OnError(error)
+	OnCompleted()
+}
+
+// Create observer quickly with function
+type ObserverMonitor[T any] struct {
+	Next      func(x T)
+	Error     func(error)
+	Completed func()
+	Context   func() context.Context // an observer context that is used when observables connected
+}
+
+func (o ObserverMonitor[T]) OnNext(x T) {
+	if o.Next != nil {
+		o.Next(x)
+	}
+}
+
+func (o ObserverMonitor[T]) OnError(e error) {
+	if o.Error != nil {
+		o.Error(e)
+	}
+}
+
+func (o ObserverMonitor[T]) OnCompleted() {
+	if o.Completed != nil {
+		o.Completed()
+	}
+}
+
+func (o ObserverMonitor[T]) GetObserverContext() context.Context {
+	if o.Context != nil {
+		return o.Context()
+	}
+	return context.Background()
+}
+
+// observer which gives the context of observables
+type observerWithContext interface {
+	GetObserverContext() context.Context
+}
+
+// item in data stream, it is a value or an error
+type item[T any] struct {
+	v   T
+	err error
+}
+
+// An Observable[T] is a 'collection of items of type T that arrive over time'.
+// Unlike rxgo.Observable, its operators always run sequentially in one goroutine.
+type Observable[T any] struct {
+	Name    string
+	buf_len uint
+	// create the outflow when connected, the chain of Observables is held by this closure
+	connect func(ctx context.Context) <-chan item[T]
+}
+
+func newObservable[T any](name string, length uint) *Observable[T] {
+	return &Observable[T]{Name: name, buf_len: length}
+}
+
+func (o *Observable[T]) SetBufferLen(length uint) *Observable[T] {
+	o.buf_len = length
+	return o
+}
+
+// Subscribe connects the pipeline and delivers items to the observer until the Observable completes.
+func (o *Observable[T]) Subscribe(ob Observer[T]) {
+	ctx := context.Background()
+	if oc, ok := ob.(observerWithContext); ok {
+		ctx = oc.GetObserverContext()
+	}
+
+	for x := range o.connect(ctx) {
+		if x.err != nil {
+			ob.OnError(x.err)
+		} else {
+			ob.OnNext(x.v)
+		}
+	}
+	ob.OnCompleted()
+}
+
+// SubscribeFunc subscribes the Observable with a function, error items are skipped.
+func (o *Observable[T]) SubscribeFunc(f func(x T)) {
+	o.Subscribe(ObserverMonitor[T]{Next: f})
+}
+
+// ToObservable converts the Observable[T] into a reflection-based rxgo.Observable.
+func (o *Observable[T]) ToObservable() *rxgo.Observable {
+	ro := rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+		for x := range o.connect(ctx) {
+			var v interface{} = x.v
+			if x.err != nil {
+				v = x.err
+			}
+			if send(v) {
+				return
+			}
+		}
+	})
+	ro.Name = o.Name
+	return ro
+}
+
+// FromObservable converts a reflection-based rxgo.Observable into Observable[T].
+// Items that are not of type T flow as FlowableError with ErrItemType.
+func FromObservable[T any](ro *rxgo.Observable) *Observable[T] {
+	o := newObservable[T]("From *rxgo.Observable", 0)
+	o.connect = func(ctx context.Context) <-chan item[T] {
+		out := make(chan item[T], o.buf_len)
+		go func() {
+			defer close(out)
+			ro.Subscribe(rxgo.ObserverMonitor{
+				Next: func(x interface{}) {
+					if v, ok := x.(T); ok {
+						sendToFlow(ctx, item[T]{v: v}, out)
+					} else {
+						sendToFlow(ctx, item[T]{err: rxgo.FlowableError{Err: ErrItemType, Elements: x}}, out)
+					}
+				},
+				Error: func(e error) {
+					sendToFlow(ctx, item[T]{err: e}, out)
+				},
+				Context: func() context.Context {
+					return ctx
+				},
+			})
+		}()
+		return out
+	}
+	return o
+}
+
+func sendToFlow[T any](ctx context.Context, x item[T], out chan<- item[T]) (end bool) {
+	select {
+	case out <- x:
+	case <-ctx.Done():
+		end = true
+	}
+	return
+}
+
+// wrap exception when call user function
+func userFuncCall[T, R any](f func(T) R, x T) (res R, skip, stop bool, eout error) {
+	defer func() {
+		if e := recover(); e != nil {
+			if fe, ok := e.(rxgo.FlowableError); ok {
+				eout = fe
+				return
+			}
+			switch e {
+			case rxgo.ErrSkipItem:
+				skip = true
+				return
+			case rxgo.ErrEoFlow:
+				stop = true
+				return
+			default:
+				panic(e)
+			}
+		}
+	}()
+
+	res = f(x)
+	return
+}
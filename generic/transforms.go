@@ -0,0 +1,98 @@
+// Copyright 2018 The SS.SYSU Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package generic
+
+import (
+	"context"
+)
+
+// transform the items of parent one by one, the handler returns true to stop the flow
+func newTransformObservable[T, R any](parent *Observable[T], name string,
+	handler func(ctx context.Context, x T, out chan<- item[R]) (end bool)) *Observable[R] {
+	o := newObservable[R](name, BufferLen)
+	o.connect = func(ctx context.Context) <-chan item[R] {
+		// the upstream has its own context, so that it can be stopped before the inflow is closed
+		uctx, cancel := context.WithCancel(ctx)
+		in := parent.connect(uctx)
+		out := make(chan item[R], o.buf_len)
+		go func() {
+			defer close(out)
+			defer cancel()
+			for x := range in {
+				if x.err != nil {
+					if sendToFlow(ctx, item[R]{err: x.err}, out) {
+						return
+					}
+					continue
+				}
+				if handler(ctx, x.v, out) {
+					return
+				}
+			}
+		}()
+		return out
+	}
+	return o
+}
+
+// Map maps each item in Observable[T] by the function and returns a new Observable[R] with applied items.
+func Map[T, R any](parent *Observable[T], f func(x T) R) *Observable[R] {
+	return newTransformObservable(parent, "map", func(ctx context.Context, x T, out chan<- item[R]) (end bool) {
+		r, skip, stop, e := userFuncCall(f, x)
+		switch {
+		case stop:
+			return true
+		case skip:
+			return false
+		case e != nil:
+			return sendToFlow(ctx, item[R]{err: e}, out)
+		}
+		return sendToFlow(ctx, item[R]{v: r}, out)
+	})
+}
+
+// Filter filters items in the original Observable[T] and returns a new Observable[T] with the filtered items.
+func Filter[T any](parent *Observable[T], f func(x T) bool) *Observable[T] {
+	return newTransformObservable(parent, "filter", func(ctx context.Context, x T, out chan<- item[T]) (end bool) {
+		b, skip, stop, e := userFuncCall(f, x)
+		switch {
+		case stop:
+			return true
+		case skip:
+			return false
+		case e != nil:
+			return sendToFlow(ctx, item[T]{err: e}, out)
+		}
+		if b {
+			return sendToFlow(ctx, item[T]{v: x}, out)
+		}
+		return false
+	})
+}
+
+// FlatMap maps each item in Observable[T] into an Observable[R] and returns a new Observable[R]
+// with items of the inner observables, each inner observable is drained before the next one.
+func FlatMap[T, R any](parent *Observable[T], f func(x T) *Observable[R]) *Observable[R] {
+	return newTransformObservable(parent, "flatMap", func(ctx context.Context, x T, out chan<- item[R]) (end bool) {
+		ro, skip, stop, e := userFuncCall(f, x)
+		switch {
+		case stop:
+			return true
+		case skip:
+			return false
+		case e != nil:
+			return sendToFlow(ctx, item[R]{err: e}, out)
+		}
+		if ro == nil {
+			return false
+		}
+		for y := range ro.connect(ctx) {
+			if sendToFlow(ctx, y, out) {
+				return true
+			}
+		}
+		return false
+	})
+}
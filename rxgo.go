@@ -32,6 +32,18 @@ var ErrEoFlow = errors.New("End of Flow!")
 // if user function throw SkipItem, the Observeable will skip current item
 var ErrSkipItem = errors.New("Skip item!")
 
+// the key of Distinct can not be used as a map key
+var ErrKeyNotComparable = errors.New("Key is not comparable!")
+
+// the aggregate of no items is undefined
+var ErrEmptyFlow = errors.New("Empty flow!")
+
+// the item can not be added as a number
+var ErrNotNumber = errors.New("Item is not a number!")
+
+// the item can not be compared with the natural order
+var ErrNotOrdered = errors.New("Item is not ordered!")
+
 // Error that can flow to subscriber or user function which processes error as an input
 type FlowableError struct {
 	Err      error
@@ -42,6 +54,11 @@ func (e FlowableError) Error() string {
 	return e.Err.Error()
 }
 
+// Unwrap returns the error of the user function, so that errors.Is and errors.As can see through it
+func (e FlowableError) Unwrap() error {
+	return e.Err
+}
+
 // Observer subscribes to an Observable. Then that observer reacts to whatever item or sequence of items the Observable emits.
 type Observer interface {
 	OnNext(x interface{})
@@ -117,6 +134,18 @@ type transformFunc func(ctx context.Context, item interface{}, send func(x inter
 // default buffer of channels
 var BufferLen uint = 128
 
+// ErrorMode decides how Subscribe delivers error items to the observer
+type ErrorMode uint
+
+const (
+	ErrorModeDefault  ErrorMode = iota // follow DefaultErrorMode
+	ErrorModeContinue                  // each error item is delivered by OnError, and the flow goes on until OnCompleted
+	ErrorModeStrict                    // the first error item stops the flow, it is delivered by OnError once and OnCompleted is not called
+)
+
+// default error mode of Observables
+var DefaultErrorMode = ErrorModeContinue
+
 // An Observable is a 'collection of items that arrive over time'. Observables can be used to model asynchronous events.
 // Observables can also be chained by operators to transformed, combined those items
 // The Observable's operators, by default, run with a channel size of 128 elements except that the source (first) observable has no buffer
@@ -131,9 +160,14 @@ type Observable struct {
 	root *Observable
 	next *Observable
 	pred *Observable
+	// stop all predecessors, it is created when connected
+	cancel_upstream context.CancelFunc
 	// control model
-	threading ThreadModel //threading model. if this is root, it represents obseverOn model
-	buf_len   uint
+	threading    ThreadModel //threading model. if this is root, it represents obseverOn model
+	buf_len      uint
+	error_mode   ErrorMode
+	pending_mode PendingMode
+	clock        Clock // clock of time-driven sources, it is SystemClock if nil
 	// utility vars
 	debug             Observer
 	flip_sup_ctx      bool //indicate that flip function use context as first paramter
@@ -146,13 +180,42 @@ func newObservable() *Observable {
 
 // connect all Observable form the first one.
 func (o *Observable) connect(ctx context.Context) {
+	po := o
+	for ; po.next != nil; po = po.next {
+	}
+	po.connectChain(ctx)
+}
+
+// connect Observables from the first one to o. Every Observable gets a context derived from
+// its downstream, so that an operator can stop its upstream without closing the downstream.
+func (o *Observable) connectChain(ctx context.Context) {
 	for po := o.root; po != nil; po = po.next {
 		po.outflow = make(chan interface{}, po.buf_len)
-		po.operator.op(ctx, po)
+		if po == o {
+			break
+		}
+	}
+	for po := o; po != nil; po = po.pred {
+		octx := ctx
+		if po.pred != nil {
+			ctx, po.cancel_upstream = context.WithCancel(ctx)
+		}
+		po.operator.op(octx, po)
 		//fmt.Println("conneted", po.name, po.outflow)
 	}
 }
 
+// connect an Observable used inside an operator and returns its outflow
+func (o *Observable) connectFlow(ctx context.Context) chan interface{} {
+	ro := o
+	for ; ro.next != nil; ro = ro.next {
+	}
+	ro.mu.Lock()
+	defer ro.mu.Unlock()
+	ro.connect(ctx)
+	return ro.outflow
+}
+
 func (o *Observable) SubscribeOn(t ThreadModel) *Observable {
 	o.threading = t
 	return o
@@ -164,6 +227,23 @@ func (o *Observable) ObserveOn(t ThreadModel) *Observable {
 	return o
 }
 
+// SetErrorMode sets the error mode of Subscribe, the mode set nearest to the observer in the chain wins.
+// The ErrorModeStrict works as ReactiveX, the first error item cancels the upstream and terminates the flow.
+func (o *Observable) SetErrorMode(mode ErrorMode) *Observable {
+	o.error_mode = mode
+	return o
+}
+
+// get the error mode of the chain ending at o
+func (o *Observable) errorMode() ErrorMode {
+	for po := o; po != nil; po = po.pred {
+		if po.error_mode != ErrorModeDefault {
+			return po.error_mode
+		}
+	}
+	return DefaultErrorMode
+}
+
 func (o *Observable) Subscribe(ob interface{}) {
 	o.mu.Lock()
 	fv, ft := reflect.ValueOf(ob), reflect.TypeOf(ob)
@@ -193,6 +273,11 @@ func (o *Observable) Subscribe(ob interface{}) {
 		//fmt.Println("ctx geted!", ctx)
 	}
 
+	// the strict mode stops all Observables at the first error item
+	strict := o.errorMode() == ErrorModeStrict
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	//fmt.Println("begin conneted", o.name)
 	o.connect(ctx)
 	if ctxok {
@@ -208,6 +293,16 @@ func (o *Observable) Subscribe(ob interface{}) {
 	o.mu.Unlock()
 
 	for x := range in {
+		if e, ok := x.(error); ok && strict {
+			// deliver the error at once, the upstream stages may take a while to stop
+			cancel()
+			if observer != nil {
+				observer.OnError(e)
+			}
+			for range in {
+			}
+			return
+		}
 		if observer != nil {
 			if e, ok := x.(error); ok {
 				observer.OnError(e)
@@ -270,6 +365,14 @@ func (o *Observable) sendToFlow(ctx context.Context, item interface{}, out chan
 	return
 }
 
+// send an error item to the outflow directly if the flip does not accept error
+func (o *Observable) forwardError(ctx context.Context, item interface{}, out chan interface{}) (forwarded, end bool) {
+	if e, ok := item.(error); ok && !o.flip_accept_error {
+		return true, o.sendToFlow(ctx, e, out)
+	}
+	return
+}
+
 func (o *Observable) closeFlow(out chan interface{}) *Observable {
 	// maybe need waiting for parent observable closed
 	//fmt.Println("close chan ", o.name, out)
@@ -0,0 +1,121 @@
+package rxgo_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pmlpml/rxgo"
+	"github.com/stretchr/testify/assert"
+)
+
+// subscribe the Observable in a goroutine and return when it is connected, the result waits for the items
+func subscribeAsync(o *rxgo.Observable) func() []interface{} {
+	res := []interface{}{}
+	connected := make(chan bool)
+	done := make(chan bool)
+	go func() {
+		o.Subscribe(rxgo.ObserverMonitor{
+			Next: func(x interface{}) {
+				res = append(res, x)
+			},
+			Error: func(e error) {
+				res = append(res, e)
+			},
+			AfterConnected: func() {
+				close(connected)
+			},
+		})
+		close(done)
+	}()
+	<-connected
+	return func() []interface{} {
+		<-done
+		return res
+	}
+}
+
+func TestPublishSubject(t *testing.T) {
+	ee := errors.New("Any")
+	s := rxgo.NewPublishSubject()
+	s.OnNext(0)
+	first := subscribeAsync(s.Observable())
+	second := subscribeAsync(s.Observable().Map(func(x int) int {
+		return 10 * x
+	}))
+	s.OnNext(1)
+	s.OnError(ee)
+	s.OnNext(2)
+	s.OnCompleted()
+	s.OnNext(3)
+
+	assert.Equal(t, []interface{}{1, ee, 2}, first(), "PublishSubject Test Error!")
+	assert.Equal(t, []interface{}{10, ee, 20}, second(), "PublishSubject Map Test Error!")
+	assert.Equal(t, []interface{}{}, subscribeAsync(s.Observable())(), "PublishSubject Completed Test Error!")
+}
+
+func TestBehaviorSubject(t *testing.T) {
+	s := rxgo.NewBehaviorSubject(0)
+	first := subscribeAsync(s.Observable())
+	s.OnNext(1)
+	second := subscribeAsync(s.Observable())
+	s.OnNext(2)
+	s.OnCompleted()
+
+	assert.Equal(t, []interface{}{0, 1, 2}, first(), "BehaviorSubject Test Error!")
+	assert.Equal(t, []interface{}{1, 2}, second(), "BehaviorSubject Latest Test Error!")
+	assert.Equal(t, []interface{}{}, subscribeAsync(s.Observable())(), "BehaviorSubject Completed Test Error!")
+}
+
+func TestReplaySubject(t *testing.T) {
+	s := rxgo.NewReplaySubject(2, 0)
+	s.OnNext(1)
+	s.OnNext(2)
+	s.OnNext(3)
+	first := subscribeAsync(s.Observable())
+	s.OnNext(4)
+	s.OnCompleted()
+
+	assert.Equal(t, []interface{}{2, 3, 4}, first(), "ReplaySubject Test Error!")
+	assert.Equal(t, []interface{}{3, 4}, subscribeAsync(s.Observable())(), "ReplaySubject Completed Test Error!")
+
+	s = rxgo.NewReplaySubject(0, 20*time.Millisecond)
+	s.OnNext(1)
+	time.Sleep(40 * time.Millisecond)
+	s.OnNext(2)
+	s.OnNext(3)
+	s.OnCompleted()
+	assert.Equal(t, []interface{}{2, 3}, subscribeAsync(s.Observable())(), "ReplaySubject Age Test Error!")
+}
+
+func TestAsyncSubject(t *testing.T) {
+	s := rxgo.NewAsyncSubject()
+	s.OnNext(1)
+	first := subscribeAsync(s.Observable())
+	s.OnNext(2)
+	s.OnCompleted()
+
+	assert.Equal(t, []interface{}{2}, first(), "AsyncSubject Test Error!")
+	assert.Equal(t, []interface{}{2}, subscribeAsync(s.Observable())(), "AsyncSubject Completed Test Error!")
+}
+
+func TestSubjectConcurrency(t *testing.T) {
+	s := rxgo.NewReplaySubject(0, 0)
+	result := subscribeAsync(s.Observable())
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s.OnNext(i*100 + j)
+			}
+		}(i)
+	}
+	wg.Wait()
+	s.OnCompleted()
+
+	assert.Len(t, result(), 1000, "Subject Concurrency Test Error!")
+	assert.Len(t, subscribeAsync(s.Observable())(), 1000, "Subject Concurrency Replay Test Error!")
+}
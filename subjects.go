@@ -0,0 +1,274 @@
+// Copyright 2018 The SS.SYSU Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rxgo
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// subject multicasts items to the subscribers of its Observables, each subscriber has its own
+// unbounded queue, so the Observer methods never block on slow subscribers.
+type subject struct {
+	mu        sync.Mutex
+	flows     map[*innerFlow]bool
+	completed bool
+}
+
+func newSubject() subject {
+	return subject{flows: make(map[*innerFlow]bool)}
+}
+
+// push x to all subscribers, the caller holds the lock
+func (s *subject) publish(x interface{}) {
+	for f := range s.flows {
+		f.push(x)
+	}
+}
+
+// complete all subscribers, the caller holds the lock
+func (s *subject) complete() {
+	for f := range s.flows {
+		f.close()
+	}
+	s.flows = nil
+	s.completed = true
+}
+
+// register a subscriber initialized by init, it is closed at once if the subject completed
+func (s *subject) subscribe(init func(f *innerFlow)) (f *innerFlow, unsubscribe func()) {
+	f = newInnerFlow()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if init != nil {
+		init(f)
+	}
+	if s.completed {
+		f.close()
+	} else {
+		s.flows[f] = true
+	}
+	return f, func() {
+		s.mu.Lock()
+		delete(s.flows, f)
+		s.mu.Unlock()
+	}
+}
+
+// create an Observable of the subject, the subscriber is initialized by init when it is connected
+func (s *subject) observable(name string, init func(f *innerFlow)) *Observable {
+	o := newGeneratorObservable(name)
+	o.flip = func() (*innerFlow, func()) {
+		return s.subscribe(init)
+	}
+	o.operator = subjectSource
+	return o
+}
+
+// subject node implementation of streamOperator, the subscriber is registered when connected,
+// so it receives all items sent after Subscribe connected the Observables.
+type subjectOperater struct{}
+
+func (subjectOperater) op(ctx context.Context, o *Observable) {
+	out := o.outflow
+	f, unsubscribe := o.flip.(func() (*innerFlow, func()))()
+
+	go func() {
+		f.emit(ctx, o, out)
+		unsubscribe()
+		o.closeFlow(out)
+	}()
+}
+
+var subjectSource = subjectOperater{}
+
+// PublishSubject emits the items to the subscribers that arrive after they subscribed.
+// It is an Observer and it is goroutine-safe.
+type PublishSubject struct {
+	subject
+}
+
+// NewPublishSubject creates a PublishSubject.
+func NewPublishSubject() *PublishSubject {
+	return &PublishSubject{newSubject()}
+}
+
+func (s *PublishSubject) OnNext(x interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.completed {
+		s.publish(x)
+	}
+}
+
+func (s *PublishSubject) OnError(e error) {
+	s.OnNext(e)
+}
+
+func (s *PublishSubject) OnCompleted() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.complete()
+}
+
+// Observable creates an Observable emitting the items of the subject.
+func (s *PublishSubject) Observable() *Observable {
+	return s.observable("PublishSubject", nil)
+}
+
+// BehaviorSubject emits the latest item, or the initial one if there is none, to each new subscriber,
+// then the items that arrive after they subscribed. It is an Observer and it is goroutine-safe.
+type BehaviorSubject struct {
+	subject
+	latest interface{}
+}
+
+// NewBehaviorSubject creates a BehaviorSubject with the initial item.
+func NewBehaviorSubject(initial interface{}) *BehaviorSubject {
+	return &BehaviorSubject{newSubject(), initial}
+}
+
+func (s *BehaviorSubject) OnNext(x interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.completed {
+		s.latest = x
+		s.publish(x)
+	}
+}
+
+// OnError emits the error item, and it does not replace the latest item.
+func (s *BehaviorSubject) OnError(e error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.completed {
+		s.publish(e)
+	}
+}
+
+func (s *BehaviorSubject) OnCompleted() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.complete()
+}
+
+// Observable creates an Observable emitting the latest item and the items of the subject.
+func (s *BehaviorSubject) Observable() *Observable {
+	return s.observable("BehaviorSubject", func(f *innerFlow) {
+		if !s.completed {
+			f.push(s.latest)
+		}
+	})
+}
+
+// ReplaySubject emits the buffered items to each new subscriber, then the items that arrive after they subscribed.
+// It is an Observer and it is goroutine-safe.
+type ReplaySubject struct {
+	subject
+	size  int
+	age   time.Duration
+	items []timedItem
+}
+
+type timedItem struct {
+	item interface{}
+	t    time.Time
+}
+
+// NewReplaySubject creates a ReplaySubject buffering the latest size items no older than age.
+// The buffer is unbounded by count if size is not positive, and by age if age is zero.
+func NewReplaySubject(size int, age time.Duration) *ReplaySubject {
+	return &ReplaySubject{subject: newSubject(), size: size, age: age}
+}
+
+// remove the items out of size or age, the caller holds the lock
+func (s *ReplaySubject) prune(now time.Time) {
+	i := 0
+	if s.size > 0 && len(s.items) > s.size {
+		i = len(s.items) - s.size
+	}
+	for ; s.age > 0 && i < len(s.items) && now.Sub(s.items[i].t) > s.age; i++ {
+	}
+	s.items = s.items[i:]
+}
+
+func (s *ReplaySubject) OnNext(x interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.completed {
+		now := time.Now()
+		s.items = append(s.items, timedItem{x, now})
+		s.prune(now)
+		s.publish(x)
+	}
+}
+
+func (s *ReplaySubject) OnError(e error) {
+	s.OnNext(e)
+}
+
+func (s *ReplaySubject) OnCompleted() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.complete()
+}
+
+// Observable creates an Observable emitting the buffered items and the items of the subject.
+func (s *ReplaySubject) Observable() *Observable {
+	return s.observable("ReplaySubject", func(f *innerFlow) {
+		s.prune(time.Now())
+		for _, x := range s.items {
+			f.push(x.item)
+		}
+	})
+}
+
+// AsyncSubject emits only the last item to the subscribers when it completes, error items are emitted at once.
+// It is an Observer and it is goroutine-safe.
+type AsyncSubject struct {
+	subject
+	last    interface{}
+	has_val bool
+}
+
+// NewAsyncSubject creates an AsyncSubject.
+func NewAsyncSubject() *AsyncSubject {
+	return &AsyncSubject{subject: newSubject()}
+}
+
+func (s *AsyncSubject) OnNext(x interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.completed {
+		s.last, s.has_val = x, true
+	}
+}
+
+func (s *AsyncSubject) OnError(e error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.completed {
+		s.publish(e)
+	}
+}
+
+func (s *AsyncSubject) OnCompleted() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.completed && s.has_val {
+		s.publish(s.last)
+	}
+	s.complete()
+}
+
+// Observable creates an Observable emitting the last item of the subject when it completes.
+func (s *AsyncSubject) Observable() *Observable {
+	return s.observable("AsyncSubject", func(f *innerFlow) {
+		if s.completed && s.has_val {
+			f.push(s.last)
+		}
+	})
+}
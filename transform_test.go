@@ -3,7 +3,11 @@ package rxgo_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/pmlpml/rxgo"
 	"github.com/stretchr/testify/assert"
@@ -97,3 +101,170 @@ func TestFilter(t *testing.T) {
 
 	assert.Equal(t, []int{0, 7, 2}, res, "Map Test Error!")
 }
+
+func TestScan(t *testing.T) {
+	res := []interface{}{}
+	ee := errors.New("Any")
+	rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+		send(1)
+		send(2)
+		send(ee)
+		send(3)
+		send(4)
+		send(5)
+	}).Scan(10, func(acc, x int) int {
+		if x == 3 {
+			panic(rxgo.ErrSkipItem)
+		}
+		if x == 5 {
+			panic(rxgo.ErrEoFlow)
+		}
+		return acc + x
+	}).SubscribeOn(rxgo.ThreadingIO).Subscribe(rxgo.ObserverMonitor{
+		Next: func(x interface{}) {
+			res = append(res, x)
+		},
+		Error: func(e error) {
+			res = append(res, e)
+		},
+	})
+
+	assert.Equal(t, []interface{}{11, 13, ee, 17}, res, "Scan Test Error!")
+}
+
+func TestConcatMap(t *testing.T) {
+	res := []int{}
+	rxgo.Just(10, 20, 30).ConcatMap(func(x int) *rxgo.Observable {
+		return rxgo.Just(x+1, x+2)
+	}).SubscribeOn(rxgo.ThreadingIO).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+
+	assert.Equal(t, []int{11, 12, 21, 22, 31, 32}, res, "ConcatMap Test Error!")
+}
+
+func TestMergeMap(t *testing.T) {
+	var active, maxActive int32
+	res := []int{}
+	rxgo.Range(0, 6).MergeMap(func(ctx context.Context, x int) *rxgo.Observable {
+		return rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+			n := atomic.AddInt32(&active, 1)
+			for m := atomic.LoadInt32(&maxActive); n > m && !atomic.CompareAndSwapInt32(&maxActive, m, n); m = atomic.LoadInt32(&maxActive) {
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&active, -1)
+			send(x)
+		})
+	}, 2).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+
+	sort.Ints(res)
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5}, res, "MergeMap Test Error!")
+	assert.True(t, atomic.LoadInt32(&maxActive) <= 2, "MergeMap Concurrency Test Error!")
+}
+
+func TestSwitchMap(t *testing.T) {
+	res := []string{}
+	next := make(chan bool)
+	rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+		send("a")
+		<-next
+		send("b")
+	}).SwitchMap(func(s string) *rxgo.Observable {
+		return rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+			for i := 0; i < 3; i++ {
+				if send(fmt.Sprint(s, i)) {
+					return
+				}
+				if s == "a" {
+					// the outer item is switched, so the inner is cancelled
+					<-ctx.Done()
+					return
+				}
+			}
+		})
+	}).Subscribe(func(x string) {
+		res = append(res, x)
+		if x == "a0" {
+			close(next)
+		}
+	})
+
+	assert.Equal(t, []string{"a0", "b0", "b1", "b2"}, res, "SwitchMap Test Error!")
+}
+
+func TestExhaustMap(t *testing.T) {
+	res := []string{}
+	release := make(chan bool)
+	rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+		send("a")
+		send("b")
+		// "b" has been ignored when the error is received, and the error is not mapped anyway
+		send(errors.New("Any"))
+		close(release)
+	}).ExhaustMap(func(s string) *rxgo.Observable {
+		return rxgo.Generator(func(ctx context.Context, send func(x interface{}) (endSignal bool)) {
+			<-release
+			send(s + "0")
+			send(s + "1")
+		})
+	}).Subscribe(func(x string) {
+		res = append(res, x)
+	})
+
+	assert.Equal(t, []string{"a0", "a1"}, res, "ExhaustMap Test Error!")
+}
+
+func TestRepeat(t *testing.T) {
+	res := []int{}
+	rxgo.Just(1, 2).Map(func(x int) int {
+		return 10 * x
+	}).Repeat(3).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []int{10, 20, 10, 20, 10, 20}, res, "Repeat Test Error!")
+
+	res = []int{}
+	rxgo.Just(1, 2, 3).Repeat(-1).Take(7).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []int{1, 2, 3, 1, 2, 3, 1}, res, "Repeat Forever Test Error!")
+
+	res = []int{}
+	rxgo.Just(1, 2).Repeat(0).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []int{}, res, "Repeat Zero Test Error!")
+}
+
+func TestRepeatWhen(t *testing.T) {
+	res := []int{}
+	runs := []int{}
+	rxgo.Just(1, 2).RepeatWhen(func(completions *rxgo.Observable) *rxgo.Observable {
+		return completions.TakeWhile(func(n int) bool {
+			runs = append(runs, n)
+			return n < 3
+		})
+	}).Subscribe(func(x int) {
+		res = append(res, x)
+	})
+	assert.Equal(t, []int{1, 2, 1, 2, 1, 2}, res, "RepeatWhen Test Error!")
+	assert.Equal(t, []int{1, 2, 3}, runs, "RepeatWhen Completions Test Error!")
+
+	res = []int{}
+	ctx, cancel := context.WithCancel(context.Background())
+	rxgo.Just(1, 2).RepeatWhen(func(completions *rxgo.Observable) *rxgo.Observable {
+		return completions
+	}).Subscribe(rxgo.ObserverMonitor{
+		Next: func(x interface{}) {
+			if res = append(res, x.(int)); len(res) == 5 {
+				cancel()
+			}
+		},
+		Context: func() context.Context {
+			return ctx
+		},
+	})
+	assert.True(t, len(res) >= 5 && len(res) < 5+2*int(rxgo.BufferLen), "RepeatWhen Cancel Test Error!")
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"reflect"
 	"sync"
+	"time"
 )
 
 var (
@@ -28,6 +29,7 @@ func (tsop transOperater) op(ctx context.Context, o *Observable) {
 	// this resurces may be changed when operation routine is running.
 	in := o.pred.outflow
 	out := o.outflow
+	cancel := o.cancel_upstream
 	//fmt.Println(o.name, "operator in/out chan ", in, out)
 	var wg sync.WaitGroup
 
@@ -35,7 +37,7 @@ func (tsop transOperater) op(ctx context.Context, o *Observable) {
 		end := false
 		for x := range in {
 			if end {
-				continue
+				break
 			}
 			// can not pass a interface as parameter (pointer) to gorountion for it may change its value outside!
 			xv := reflect.ValueOf(x)
@@ -66,6 +68,78 @@ func (tsop transOperater) op(ctx context.Context, o *Observable) {
 
 		wg.Wait() //waiting all go-routines completed
 		o.closeFlow(out)
+		// stop the upstream and drain the rest items
+		cancel()
+		for range in {
+		}
+	}()
+}
+
+// flow node implementation of streamOperator, the operator consumes the inflow by itself,
+// so items are processed sequentially whatever the threading model is.
+type flowOperater struct {
+	opFunc func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{})
+}
+
+func (fop flowOperater) op(ctx context.Context, o *Observable) {
+	in := o.pred.outflow
+	out := o.outflow
+	cancel := o.cancel_upstream
+
+	go func() {
+		fop.opFunc(ctx, o, in, out)
+		o.closeFlow(out)
+		// the operator may return before the inflow closed, stop the upstream and drain it
+		cancel()
+		for range in {
+		}
+	}()
+}
+
+// upstream node implementation of streamOperator, the operator consumes the inflow by itself
+// and it can stop the upstream or connect it again.
+type upstreamOperater struct {
+	opFunc func(ctx context.Context, o *Observable, up *upstream, out chan interface{})
+}
+
+// upstream is the inflow of an upstreamOperater with the control of the upstream Observables
+type upstream struct {
+	ctx    context.Context
+	o      *Observable
+	in     chan interface{}
+	cancel context.CancelFunc
+}
+
+// stop the upstream and drain the inflow
+func (up *upstream) stop() {
+	up.cancel()
+	for range up.in {
+	}
+}
+
+// stop the upstream and connect it again after the delay, returns nil if ctx is done
+func (up *upstream) reconnect(delay time.Duration) chan interface{} {
+	up.stop()
+	select {
+	case <-time.After(delay):
+	case <-up.ctx.Done():
+		return nil
+	}
+	var uctx context.Context
+	uctx, up.cancel = context.WithCancel(up.ctx)
+	up.o.pred.connectChain(uctx)
+	up.in = up.o.pred.outflow
+	return up.in
+}
+
+func (uop upstreamOperater) op(ctx context.Context, o *Observable) {
+	up := &upstream{ctx: ctx, o: o, in: o.pred.outflow, cancel: o.cancel_upstream}
+	out := o.outflow
+
+	go func() {
+		uop.opFunc(ctx, o, up, out)
+		o.closeFlow(out)
+		up.stop()
 	}()
 }
 
@@ -113,10 +187,8 @@ func (parent *Observable) Map(f interface{}) (o *Observable) {
 var mapOperater = transOperater{func(ctx context.Context, o *Observable, x reflect.Value, out chan interface{}) (end bool) {
 
 	fv := reflect.ValueOf(o.flip)
-	var params = []reflect.Value{x}
-	rs, skip, stop, e := userFuncCall(fv, params)
+	rs, skip, stop, e := userFuncCallWithContext(ctx, fv, o.flip_sup_ctx, x)
 
-	var item interface{} = rs[0].Interface()
 	if stop {
 		end = true
 		return
@@ -124,8 +196,9 @@ var mapOperater = transOperater{func(ctx context.Context, o *Observable, x refle
 	if skip {
 		return
 	}
-	if e != nil {
-		item = e
+	var item interface{} = e
+	if e == nil {
+		item = rs[0].Interface()
 	}
 	// send data
 	if !end {
@@ -159,11 +232,8 @@ func (parent *Observable) FlatMap(f interface{}) (o *Observable) {
 var flatMapOperater = transOperater{func(ctx context.Context, o *Observable, x reflect.Value, out chan interface{}) (end bool) {
 
 	fv := reflect.ValueOf(o.flip)
-	var params = []reflect.Value{x}
 	//fmt.Println("x is ", x)
-	rs, skip, stop, e := userFuncCall(fv, params)
-
-	var item = rs[0].Interface().(*Observable)
+	rs, skip, stop, e := userFuncCallWithContext(ctx, fv, o.flip_sup_ctx, x)
 
 	if stop {
 		end = true
@@ -179,6 +249,7 @@ var flatMapOperater = transOperater{func(ctx context.Context, o *Observable, x r
 		}
 		return
 	}
+	var item = rs[0].Interface().(*Observable)
 	// send data
 	if !end {
 		if item != nil {
@@ -200,6 +271,205 @@ var flatMapOperater = transOperater{func(ctx context.Context, o *Observable, x r
 	return
 }}
 
+// ConcatMap maps each item in Observable by the function with `func(x anytype) (o *Observable)` and
+// emits items of the inner observables one after another whatever the threading model is.
+func (parent *Observable) ConcatMap(f interface{}) (o *Observable) {
+	o = parent.newFlattenObservable("concatMap", f)
+	o.operator = concatMapOperater
+	return o
+}
+
+var concatMapOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	for x := range in {
+		inner, end := o.innerObservable(ctx, x, out)
+		if end {
+			return
+		}
+		if inner == nil {
+			continue
+		}
+		for y := range inner.connectFlow(ctx) {
+			if o.sendToFlow(ctx, y, out) {
+				return
+			}
+		}
+	}
+}}
+
+// MergeMap maps each item in Observable by the function with `func(x anytype) (o *Observable)` and
+// merges items of at most maxConcurrency inner observables concurrently, it is unlimited if maxConcurrency is not positive.
+func (parent *Observable) MergeMap(f interface{}, maxConcurrency int) (o *Observable) {
+	o = parent.newFlattenObservable("mergeMap", f)
+	o.flip.(*flattenFlip).concurrency = maxConcurrency
+	o.operator = mergeMapOperater
+	return o
+}
+
+var mergeMapOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	var slots chan struct{}
+	if n := o.flip.(*flattenFlip).concurrency; n > 0 {
+		slots = make(chan struct{}, n)
+	}
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for x := range in {
+		inner, end := o.innerObservable(ctx, x, out)
+		if end {
+			return
+		}
+		if inner == nil {
+			continue
+		}
+		if slots != nil {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+		}
+		wg.Add(1)
+		go func(flow chan interface{}) {
+			defer wg.Done()
+			for y := range flow {
+				if o.sendToFlow(ctx, y, out) {
+					break
+				}
+			}
+			if slots != nil {
+				<-slots
+			}
+		}(inner.connectFlow(ctx))
+	}
+}}
+
+// SwitchMap maps each item in Observable by the function with `func(x anytype) (o *Observable)` and
+// emits items of the latest inner observable, the previous one is stopped when a new item arrives.
+func (parent *Observable) SwitchMap(f interface{}) (o *Observable) {
+	o = parent.newFlattenObservable("switchMap", f)
+	o.operator = switchMapOperater
+	return o
+}
+
+var switchMapOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	cancel := context.CancelFunc(func() {})
+	done := make(chan bool)
+	close(done)
+	defer func() {
+		<-done
+	}()
+	for x := range in {
+		inner, end := o.innerObservable(ctx, x, out)
+		if end {
+			cancel()
+			return
+		}
+		if inner == nil {
+			continue
+		}
+		// stop the previous inner observable, no item of it is emitted after it exits
+		cancel()
+		<-done
+
+		var ictx context.Context
+		ictx, cancel = context.WithCancel(ctx)
+		done = make(chan bool)
+		go func(ictx context.Context, cancel context.CancelFunc, done chan bool) {
+			defer close(done)
+			defer cancel()
+			for y := range inner.connectFlow(ictx) {
+				if o.sendToFlow(ictx, y, out) {
+					return
+				}
+			}
+		}(ictx, cancel, done)
+	}
+}}
+
+// ExhaustMap maps each item in Observable by the function with `func(x anytype) (o *Observable)` and
+// emits items of the inner observable, items arrived are ignored while the inner observable is active.
+func (parent *Observable) ExhaustMap(f interface{}) (o *Observable) {
+	o = parent.newFlattenObservable("exhaustMap", f)
+	o.operator = exhaustMapOperater
+	return o
+}
+
+var exhaustMapOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	done := make(chan bool)
+	close(done)
+	defer func() {
+		<-done
+	}()
+	for x := range in {
+		select {
+		case <-done:
+		default:
+			// the inner observable is active
+			if forwarded, end := o.forwardError(ctx, x, out); forwarded && end {
+				return
+			}
+			continue
+		}
+		inner, end := o.innerObservable(ctx, x, out)
+		if end {
+			return
+		}
+		if inner == nil {
+			continue
+		}
+		done = make(chan bool)
+		go func(flow chan interface{}, done chan bool) {
+			defer close(done)
+			for y := range flow {
+				if o.sendToFlow(ctx, y, out) {
+					return
+				}
+			}
+		}(inner.connectFlow(ctx), done)
+	}
+}}
+
+// check validation of f `func(x anytype) (o *Observable)` for flattening operators
+func (parent *Observable) newFlattenObservable(name string, f interface{}) (o *Observable) {
+	fv := reflect.ValueOf(f)
+	inType := []reflect.Type{typeAny}
+	outType := []reflect.Type{typeObservable}
+	b, ctx_sup := checkFuncUpcast(fv, inType, outType, true)
+	if !b {
+		panic(ErrFuncFlip)
+	}
+
+	o = parent.newTransformObservable(name)
+	o.flip_accept_error = checkFuncAcceptError(fv)
+
+	o.flip_sup_ctx = ctx_sup
+	o.flip = &flattenFlip{fv: fv}
+	return o
+}
+
+// flip of flattening operators
+type flattenFlip struct {
+	fv          reflect.Value
+	concurrency int
+}
+
+// map x to an inner observable, it is nil if x is an error item or the function throws an error
+func (o *Observable) innerObservable(ctx context.Context, x interface{}, out chan interface{}) (inner *Observable, end bool) {
+	if forwarded, end := o.forwardError(ctx, x, out); forwarded {
+		return nil, end
+	}
+	rs, skip, stop, e := userFuncCallWithContext(ctx, o.flip.(*flattenFlip).fv, o.flip_sup_ctx, reflect.ValueOf(x))
+	if stop {
+		return nil, true
+	}
+	if skip {
+		return nil, false
+	}
+	if e != nil {
+		return nil, o.sendToFlow(ctx, e, out)
+	}
+	return rs[0].Interface().(*Observable), false
+}
+
 // Filter `func(x anytype) bool` filters items in the original Observable and returns
 // a new Observable with the filtered items.
 func (parent *Observable) Filter(f interface{}) (o *Observable) {
@@ -224,10 +494,8 @@ func (parent *Observable) Filter(f interface{}) (o *Observable) {
 var filterOperater = transOperater{func(ctx context.Context, o *Observable, x reflect.Value, out chan interface{}) (end bool) {
 
 	fv := reflect.ValueOf(o.flip)
-	var params = []reflect.Value{x}
-	rs, skip, stop, e := userFuncCall(fv, params)
+	rs, skip, stop, e := userFuncCallWithContext(ctx, fv, o.flip_sup_ctx, x)
 
-	var item interface{} = rs[0].Interface()
 	if stop {
 		end = true
 		return
@@ -235,8 +503,9 @@ var filterOperater = transOperater{func(ctx context.Context, o *Observable, x re
 	if skip {
 		return
 	}
-	if e != nil {
-		item = e
+	var item interface{} = e
+	if e == nil {
+		item = rs[0].Interface()
 	}
 	// send data
 	if !end {
@@ -248,6 +517,178 @@ var filterOperater = transOperater{func(ctx context.Context, o *Observable, x re
 	return
 }}
 
+// Scan `func(acc anytype, x anytype) anytype` applies the function to the seed and the first item,
+// then feeds the result with the second item into the function and so on, it emits every result.
+// Error items are not accumulated but flow to the downstream. Items are processed sequentially.
+func (parent *Observable) Scan(seed interface{}, f interface{}) (o *Observable) {
+	o = parent.newTransformObservable("scan")
+	o.flip = newAccumulator(seed, f)
+	o.operator = scanOperater
+	return o
+}
+
+var scanOperater = flowOperater{func(ctx context.Context, o *Observable, in chan interface{}, out chan interface{}) {
+	acc := *o.flip.(*accumulator)
+	for x := range in {
+		if forwarded, end := o.forwardError(ctx, x, out); forwarded {
+			if end {
+				return
+			}
+			continue
+		}
+		skip, stop, e := acc.accumulate(ctx, x)
+		if stop {
+			return
+		}
+		if skip {
+			continue
+		}
+		item := acc.value()
+		if e != nil {
+			item = e
+		}
+		if o.sendToFlow(ctx, item, out) {
+			return
+		}
+	}
+}}
+
+// accumulator of Scan or Reduce
+type accumulator struct {
+	fv      reflect.Value
+	ctx_sup bool
+	acc     reflect.Value
+}
+
+// check validation of f `func(acc anytype, x anytype) anytype` and the seed
+func newAccumulator(seed interface{}, f interface{}) *accumulator {
+	fv := reflect.ValueOf(f)
+	inType := []reflect.Type{typeAny, typeAny}
+	outType := []reflect.Type{typeAny}
+	b, ctx_sup := checkFuncUpcast(fv, inType, outType, true)
+	if !b {
+		panic(ErrFuncFlip)
+	}
+
+	accType := fv.Type().In(0)
+	if ctx_sup {
+		accType = fv.Type().In(1)
+	}
+	if !fv.Type().Out(0).AssignableTo(accType) {
+		panic(ErrFuncFlip)
+	}
+	acc := reflect.Zero(accType)
+	if seed != nil {
+		if acc = reflect.ValueOf(seed); !acc.Type().AssignableTo(accType) {
+			panic(ErrFuncFlip)
+		}
+	}
+	return &accumulator{fv, ctx_sup, acc}
+}
+
+// apply the function to the accumulator and x, the accumulator is unchanged if an error is thrown
+func (a *accumulator) accumulate(ctx context.Context, x interface{}) (skip, stop bool, e error) {
+	rs, skip, stop, e := userFuncCallWithContext(ctx, a.fv, a.ctx_sup, a.acc, reflect.ValueOf(x))
+	if skip || stop || e != nil {
+		return
+	}
+	a.acc = rs[0]
+	return
+}
+
+func (a *accumulator) value() interface{} {
+	return a.acc.Interface()
+}
+
+// Repeat emits the items of the upstream Observables n times, it connects the upstream again when it completes.
+// It repeats forever if n is negative.
+func (parent *Observable) Repeat(n int) (o *Observable) {
+	o = parent.newTransformObservable("repeat")
+	o.flip = n
+	o.operator = repeatOperater
+	return o
+}
+
+var repeatOperater = upstreamOperater{func(ctx context.Context, o *Observable, up *upstream, out chan interface{}) {
+	n := o.flip.(int)
+	if n == 0 {
+		return
+	}
+	in := up.in
+	for i := 1; ; i++ {
+		for x := range in {
+			if o.sendToFlow(ctx, x, out) {
+				return
+			}
+		}
+		if i == n {
+			return
+		}
+		if in = up.reconnect(0); in == nil {
+			return
+		}
+	}
+}}
+
+// RepeatWhen connects the upstream again when the Observable returned by the notifier emits an item after
+// the upstream completed. The notifier gets the Observable of completions, which emits the count of completed
+// runs 1, 2 ... It completes when the returned Observable completes and the upstream is not running.
+func (parent *Observable) RepeatWhen(notifier func(completions *Observable) *Observable) (o *Observable) {
+	o = parent.newTransformObservable("repeatWhen")
+	o.flip = notifier
+	o.operator = repeatWhenOperater
+	return o
+}
+
+var repeatWhenOperater = upstreamOperater{func(ctx context.Context, o *Observable, up *upstream, out chan interface{}) {
+	completions := newInnerFlow()
+	defer completions.close()
+	notifier := o.flip.(func(completions *Observable) *Observable)(completions.observable("completions"))
+	if notifier == nil {
+		notifier = Empty()
+	}
+	nctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	signal := notifier.connectFlow(nctx)
+
+	in := up.in
+	runs := 0
+	for {
+		select {
+		case x, ok := <-in:
+			if !ok {
+				if signal == nil {
+					return
+				}
+				in = nil
+				runs++
+				completions.push(runs)
+				continue
+			}
+			if o.sendToFlow(ctx, x, out) {
+				return
+			}
+		case _, ok := <-signal:
+			if !ok {
+				if in == nil {
+					return
+				}
+				// complete when the running upstream completes
+				signal = nil
+				continue
+			}
+			// signals are ignored while the upstream is running
+			if in == nil {
+				if in = up.reconnect(0); in == nil {
+					return
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}}
+
 func (parent *Observable) newTransformObservable(name string) (o *Observable) {
 	//new Observable
 	o = newObservable()
@@ -5,6 +5,7 @@
 package rxgo
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 )
@@ -142,3 +143,11 @@ func userFuncCall(fv reflect.Value, params []reflect.Value) (res []reflect.Value
 	res = fv.Call(params)
 	return
 }
+
+// wrap exception when call user function, ctx is passed as the first parameter if the function supports it
+func userFuncCallWithContext(ctx context.Context, fv reflect.Value, ctx_sup bool, params ...reflect.Value) (res []reflect.Value, skip, stop bool, eout error) {
+	if ctx_sup {
+		params = append([]reflect.Value{reflect.ValueOf(ctx)}, params...)
+	}
+	return userFuncCall(fv, params)
+}